@@ -0,0 +1,260 @@
+package kml
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// An ABGR is a KML color. Its bytes, from most to least significant, are the
+// alpha, blue, green, and red components, so its hexadecimal representation is
+// the aabbggrr used in KML documents.
+//
+// As in KML, the red, green, and blue components are not premultiplied by
+// alpha. ABGR implements color.Color, so its RGBA method returns premultiplied
+// components, but Color, BgColor, TextColor, GxOuterColor, and other
+// color-valued elements write ABGRs unchanged.
+type ABGR uint32
+
+// Palettes. Inferno, Magma, Plasma, and Viridis are the perceptually uniform
+// palettes from matplotlib.
+//
+// See https://bids.github.io/colormap/.
+var (
+	Inferno = Palette{
+		NewABGR(0x00, 0x00, 0x04, 0xff),
+		NewABGR(0x16, 0x0b, 0x39, 0xff),
+		NewABGR(0x42, 0x0a, 0x68, 0xff),
+		NewABGR(0x6a, 0x17, 0x6e, 0xff),
+		NewABGR(0x93, 0x26, 0x67, 0xff),
+		NewABGR(0xbc, 0x37, 0x54, 0xff),
+		NewABGR(0xdd, 0x51, 0x3a, 0xff),
+		NewABGR(0xf3, 0x78, 0x19, 0xff),
+		NewABGR(0xfc, 0xa5, 0x0a, 0xff),
+		NewABGR(0xf6, 0xd7, 0x46, 0xff),
+		NewABGR(0xfc, 0xff, 0xa4, 0xff),
+	}
+	Magma = Palette{
+		NewABGR(0x00, 0x00, 0x04, 0xff),
+		NewABGR(0x14, 0x0e, 0x36, 0xff),
+		NewABGR(0x3b, 0x0f, 0x70, 0xff),
+		NewABGR(0x64, 0x1a, 0x80, 0xff),
+		NewABGR(0x8c, 0x29, 0x81, 0xff),
+		NewABGR(0xb7, 0x37, 0x79, 0xff),
+		NewABGR(0xde, 0x49, 0x68, 0xff),
+		NewABGR(0xf7, 0x70, 0x5c, 0xff),
+		NewABGR(0xfe, 0x9f, 0x6d, 0xff),
+		NewABGR(0xfe, 0xcf, 0x92, 0xff),
+		NewABGR(0xfc, 0xfd, 0xbf, 0xff),
+	}
+	Plasma = Palette{
+		NewABGR(0x0d, 0x08, 0x87, 0xff),
+		NewABGR(0x41, 0x04, 0x9d, 0xff),
+		NewABGR(0x6a, 0x00, 0xa8, 0xff),
+		NewABGR(0x8f, 0x0d, 0xa4, 0xff),
+		NewABGR(0xb1, 0x2a, 0x90, 0xff),
+		NewABGR(0xcc, 0x47, 0x78, 0xff),
+		NewABGR(0xe1, 0x64, 0x62, 0xff),
+		NewABGR(0xf2, 0x84, 0x4b, 0xff),
+		NewABGR(0xfc, 0xa6, 0x36, 0xff),
+		NewABGR(0xfc, 0xce, 0x25, 0xff),
+		NewABGR(0xf0, 0xf9, 0x21, 0xff),
+	}
+	Viridis = Palette{
+		NewABGR(0x44, 0x01, 0x54, 0xff),
+		NewABGR(0x48, 0x24, 0x75, 0xff),
+		NewABGR(0x41, 0x44, 0x87, 0xff),
+		NewABGR(0x35, 0x5f, 0x8d, 0xff),
+		NewABGR(0x2a, 0x78, 0x8e, 0xff),
+		NewABGR(0x21, 0x91, 0x8c, 0xff),
+		NewABGR(0x22, 0xa8, 0x84, 0xff),
+		NewABGR(0x44, 0xbf, 0x70, 0xff),
+		NewABGR(0x7a, 0xd1, 0x51, 0xff),
+		NewABGR(0xbd, 0xdf, 0x26, 0xff),
+		NewABGR(0xfd, 0xe7, 0x25, 0xff),
+	}
+)
+
+// ABGRModel converts colors to ABGRs, un-premultiplying their components.
+var ABGRModel = color.ModelFunc(func(c color.Color) color.Color {
+	if c, ok := c.(ABGR); ok {
+		return c
+	}
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA) //nolint:forcetypeassert
+	return NewABGR(nrgba.R, nrgba.G, nrgba.B, nrgba.A)
+})
+
+// A Palette is a sequence of colors evenly spaced from zero to one.
+type Palette []ABGR
+
+// NewABGR returns a new ABGR with the given components.
+func NewABGR(r, g, b, a uint8) ABGR {
+	return ABGR(a)<<24 | ABGR(b)<<16 | ABGR(g)<<8 | ABGR(r)
+}
+
+// HSL returns an opaque ABGR from hue h in degrees and saturation s and
+// lightness l from zero to one.
+func HSL(h, s, l float64) ABGR {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s = min(max(s, 0), 1)
+	l = min(max(l, 0), 1)
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	m := l - chroma/2
+	return NewABGR(unitToByte(r+m), unitToByte(g+m), unitToByte(b+m), 0xff)
+}
+
+// Interpolate returns the color at t, from zero to one, between c1 and c2.
+// Each component is interpolated linearly.
+func Interpolate(c1, c2 ABGR, t float64) ABGR {
+	t = min(max(t, 0), 1)
+	lerp := func(shift int) ABGR {
+		a, b := float64(c1>>shift&0xff), float64(c2>>shift&0xff)
+		return ABGR(math.Round(a+t*(b-a))) << shift
+	}
+	return lerp(24) | lerp(16) | lerp(8) | lerp(0)
+}
+
+// ParseABGR parses a KML color in the form aabbggrr.
+func ParseABGR(s string) (ABGR, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 8 {
+		return 0, fmt.Errorf("%s: invalid color", s)
+	}
+	value, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid color", s)
+	}
+	return ABGR(value), nil
+}
+
+// ParseHex parses a color in the form #rrggbb or #rrggbbaa, as used in HTML
+// and CSS. The leading # is optional. Colors without an alpha component are
+// opaque.
+func ParseHex(s string) (ABGR, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return 0, fmt.Errorf("%s: invalid color", s)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid color", s)
+	}
+	if len(hex) == 6 {
+		value = value<<8 | 0xff
+	}
+	return NewABGR(uint8(value>>24), uint8(value>>16), uint8(value>>8), uint8(value)), nil //nolint:gosec
+}
+
+// Hex returns c in the form #rrggbbaa.
+func (c ABGR) Hex() string {
+	nrgba := c.NRGBA()
+	return fmt.Sprintf("#%02x%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B, nrgba.A)
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText.
+func (c ABGR) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// NRGBA returns c as a color.NRGBA.
+func (c ABGR) NRGBA() color.NRGBA {
+	return color.NRGBA{
+		R: uint8(c),       //nolint:gosec
+		G: uint8(c >> 8),  //nolint:gosec
+		B: uint8(c >> 16), //nolint:gosec
+		A: uint8(c >> 24), //nolint:gosec
+	}
+}
+
+// Random returns a color as drawn by Google Earth for c with a colorMode of
+// random: the red, green, and blue components are each scaled by an
+// independent random value from zero to one and the alpha component is
+// unchanged. It is useful for previewing styles with random colors, whose
+// color is the maximum color drawn.
+func (c ABGR) Random(rng *rand.Rand) ABGR {
+	nrgba := c.NRGBA()
+	scale := func(x uint8) uint8 {
+		return uint8(math.Round(float64(x) * rng.Float64()))
+	}
+	return NewABGR(scale(nrgba.R), scale(nrgba.G), scale(nrgba.B), nrgba.A)
+}
+
+// RGBA implements image/color.Color.RGBA.
+func (c ABGR) RGBA() (uint32, uint32, uint32, uint32) {
+	return c.NRGBA().RGBA()
+}
+
+// String returns c in the form aabbggrr.
+func (c ABGR) String() string {
+	return fmt.Sprintf("%08x", uint32(c))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText.
+func (c *ABGR) UnmarshalText(text []byte) error {
+	value, err := ParseABGR(string(text))
+	if err != nil {
+		return err
+	}
+	*c = value
+	return nil
+}
+
+// WithAlpha returns c with alpha component a.
+func (c ABGR) WithAlpha(a uint8) ABGR {
+	return c&0x00ffffff | ABGR(a)<<24
+}
+
+// At returns the color at t, from zero to one, interpolating between adjacent
+// colors in p. Values of t outside the range are clamped. It returns zero if p
+// is empty.
+func (p Palette) At(t float64) ABGR {
+	switch len(p) {
+	case 0:
+		return 0
+	case 1:
+		return p[0]
+	}
+	x := min(max(t, 0), 1) * float64(len(p)-1)
+	i := min(int(x), len(p)-2)
+	return Interpolate(p[i], p[i+1], x-float64(i))
+}
+
+// Colors returns n colors evenly spaced from the first to the last color in p.
+func (p Palette) Colors(n int) []ABGR {
+	colors := make([]ABGR, n)
+	for i := range colors {
+		if n == 1 {
+			colors[i] = p.At(0)
+		} else {
+			colors[i] = p.At(float64(i) / float64(n-1))
+		}
+	}
+	return colors
+}
+
+// unitToByte converts x from zero to one to a byte.
+func unitToByte(x float64) uint8 {
+	return uint8(math.Round(min(max(x, 0), 1) * 0xff))
+}
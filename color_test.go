@@ -0,0 +1,173 @@
+package kml_test
+
+import (
+	"encoding/xml"
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	kml "github.com/twpayne/go-kml/v3"
+)
+
+func TestABGR(t *testing.T) {
+	c := kml.NewABGR(0x12, 0x34, 0x56, 0x78)
+	assert.Equal(t, kml.ABGR(0x78563412), c)
+	assert.Equal(t, "78563412", c.String())
+	assert.Equal(t, "#12345678", c.Hex())
+	assert.Equal(t, color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0x78}, c.NRGBA())
+	assert.Equal(t, kml.ABGR(0xff563412), c.WithAlpha(0xff))
+
+	r, g, b, a := c.RGBA()
+	assert.Equal(t, [4]uint32{0x0880, 0x1890, 0x28a0, 0x7878}, [4]uint32{r, g, b, a})
+	assert.Equal(t, color.Color(c), kml.ABGRModel.Convert(c))
+	assert.Equal(t, color.Color(kml.ABGR(0xff0000ff)), kml.ABGRModel.Convert(color.RGBA{R: 0xff, A: 0xff}))
+	assert.Equal(t, color.Color(kml.ABGR(0x800000ff)), kml.ABGRModel.Convert(color.RGBA{R: 0x80, A: 0x80}))
+	assert.Equal(t, color.Color(kml.ABGR(0x80ffffff)), kml.ABGRModel.Convert(color.NRGBAModel.Convert(kml.ABGR(0x80ffffff))))
+
+	text, err := c.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "78563412", string(text))
+	var actual kml.ABGR
+	assert.NoError(t, actual.UnmarshalText([]byte("7856341a")))
+	assert.Equal(t, kml.ABGR(0x7856341a), actual)
+	assert.Error(t, actual.UnmarshalText([]byte("red")))
+}
+
+func TestABGRElements(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		element  kml.Element
+		expected string
+	}{
+		{
+			name:     "bgColor",
+			element:  kml.BgColor(kml.ABGR(0x7f0000ff)),
+			expected: `<bgColor>7f0000ff</bgColor>`,
+		},
+		{
+			name:     "color",
+			element:  kml.Color(kml.NewABGR(0xff, 0x80, 0x00, 0x40)),
+			expected: `<color>400080ff</color>`,
+		},
+		{
+			name:     "textColor",
+			element:  kml.TextColor(kml.HSL(120, 1, 0.5)),
+			expected: `<textColor>ff00ff00</textColor>`,
+		},
+		{
+			name:     "gxOuterColor",
+			element:  kml.GxOuterColor(kml.ABGR(0)),
+			expected: `<gx:outerColor>00000000</gx:outerColor>`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := xml.Marshal(tc.element)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(actual))
+		})
+	}
+}
+
+func TestABGRRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec
+	base := kml.ABGR(0x8000ff00)
+	for range 100 {
+		c := base.Random(rng).NRGBA()
+		assert.Equal(t, uint8(0), c.R)
+		assert.Equal(t, uint8(0), c.B)
+		assert.Equal(t, uint8(0x80), c.A)
+	}
+}
+
+func TestHSL(t *testing.T) {
+	for _, tc := range []struct {
+		h, s, l  float64
+		expected kml.ABGR
+	}{
+		{h: 0, s: 0, l: 0, expected: 0xff000000},
+		{h: 0, s: 0, l: 1, expected: 0xffffffff},
+		{h: 0, s: 1, l: 0.5, expected: 0xff0000ff},
+		{h: 60, s: 1, l: 0.5, expected: 0xff00ffff},
+		{h: 240, s: 1, l: 0.5, expected: 0xffff0000},
+		{h: -120, s: 1, l: 0.5, expected: 0xffff0000},
+		{h: 300, s: 1, l: 0.25, expected: 0xff800080},
+		{h: 210, s: 0.5, l: 0.5, expected: 0xffbf8040},
+	} {
+		assert.Equal(t, tc.expected, kml.HSL(tc.h, tc.s, tc.l))
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	c1, c2 := kml.ABGR(0x00000000), kml.ABGR(0xff8040ff)
+	assert.Equal(t, c1, kml.Interpolate(c1, c2, -1))
+	assert.Equal(t, c1, kml.Interpolate(c1, c2, 0))
+	assert.Equal(t, kml.ABGR(0x80402080), kml.Interpolate(c1, c2, 0.5))
+	assert.Equal(t, c2, kml.Interpolate(c1, c2, 1))
+	assert.Equal(t, c2, kml.Interpolate(c1, c2, 2))
+}
+
+func TestPalette(t *testing.T) {
+	p := kml.Palette{0xff000000, 0xff0000ff, 0xffffffff}
+	assert.Equal(t, kml.ABGR(0xff000000), p.At(0))
+	assert.Equal(t, kml.ABGR(0xff000080), p.At(0.25))
+	assert.Equal(t, kml.ABGR(0xff0000ff), p.At(0.5))
+	assert.Equal(t, kml.ABGR(0xffffffff), p.At(1))
+	assert.Equal(t, []kml.ABGR{0xff000000, 0xff0000ff, 0xffffffff}, p.Colors(3))
+	assert.Equal(t, []kml.ABGR{0xff000000}, p.Colors(1))
+	assert.Equal(t, kml.Viridis[0], kml.Viridis.At(0))
+	assert.Equal(t, kml.Viridis[10], kml.Viridis.At(1))
+	assert.Equal(t, kml.ABGR(0), kml.Palette{}.At(0.5))
+	assert.Equal(t, []kml.ABGR{0, 0}, kml.Palette{}.Colors(2))
+}
+
+func TestParseABGR(t *testing.T) {
+	for _, tc := range []struct {
+		s           string
+		expected    kml.ABGR
+		expectedErr bool
+	}{
+		{s: "ff0000ff", expected: 0xff0000ff},
+		{s: "7FFFAA00", expected: 0x7fffaa00},
+		{s: " 00000000\n", expected: 0},
+		{s: "0000ff", expectedErr: true},
+		{s: "ff0000fg", expectedErr: true},
+		{s: "#ff0000ff", expectedErr: true},
+	} {
+		t.Run(tc.s, func(t *testing.T) {
+			actual, err := kml.ParseABGR(tc.s)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	for _, tc := range []struct {
+		s           string
+		expected    kml.ABGR
+		expectedErr bool
+	}{
+		{s: "#ff0000", expected: 0xff0000ff},
+		{s: "#FF000080", expected: 0x800000ff},
+		{s: "123456", expected: 0xff563412},
+		{s: "#12345678", expected: 0x78563412},
+		{s: "#fff", expectedErr: true},
+		{s: "#gggggg", expectedErr: true},
+	} {
+		t.Run(tc.s, func(t *testing.T) {
+			actual, err := kml.ParseHex(tc.s)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package ellipsoid contains convenience methods for generating coordinates on
+// an ellipsoid. All angles are measured in degrees. Geodesics are calculated
+// with Vincenty's formulae, which are accurate to within 0.5mm on the Earth's
+// ellipsoid but may fail to converge for nearly antipodal points.
+//
+// See https://en.wikipedia.org/wiki/Vincenty%27s_formulae.
+package ellipsoid
+
+import (
+	"math"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+const (
+	degrees = 180 / math.Pi
+	radians = math.Pi / 180
+
+	epsilon       = 1e-12
+	maxIterations = 200
+)
+
+// A T is an ellipsoid with semi-major axis A and flattening F.
+type T struct {
+	A float64
+	F float64
+}
+
+var (
+	// GRS80 is the GRS80 ellipsoid, measured in meters.
+	GRS80 = T{A: 6378137, F: 1 / 298.257222101}
+
+	// WGS84 is the WGS84 ellipsoid, measured in meters.
+	WGS84 = T{A: 6378137, F: 1 / 298.257223563}
+)
+
+// Offset returns the coordinate at distance from origin in direction bearing.
+func (t T) Offset(origin kml.Coordinate, distance, bearing float64) kml.Coordinate {
+	b := t.b()
+	sinAlpha1, cosAlpha1 := math.Sincos(bearing * radians)
+	tanU1 := (1 - t.F) * math.Tan(origin.Lat*radians)
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	sigma1 := math.Atan2(tanU1, cosAlpha1)
+	sinAlpha := cosU1 * sinAlpha1
+	cosSqAlpha := 1 - sinAlpha*sinAlpha
+	uSq := cosSqAlpha * (t.A*t.A - b*b) / (b * b)
+	a, bb := vincentyAB(uSq)
+
+	sigma := distance / (b * a)
+	var sinSigma, cosSigma, cos2SigmaM float64
+	for range maxIterations {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+		deltaSigma := vincentyDeltaSigma(bb, sinSigma, cosSigma, cos2SigmaM)
+		prevSigma := sigma
+		sigma = distance/(b*a) + deltaSigma
+		if math.Abs(sigma-prevSigma) <= epsilon {
+			break
+		}
+	}
+	sinSigma, cosSigma = math.Sincos(sigma)
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+
+	x := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	lat2 := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-t.F)*math.Hypot(sinAlpha, x))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	c := t.F / 16 * cosSqAlpha * (4 + t.F*(4-3*cosSqAlpha))
+	l := lambda - (1-c)*t.F*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+	return kml.Coordinate{
+		Lon: origin.Lon + l*degrees,
+		Lat: lat2 * degrees,
+		Alt: origin.Alt,
+	}
+}
+
+// Circle returns an array of kml.Coordinates that approximate a circle of the
+// given radius centered on center with a maximum error of maxErr.
+func (t T) Circle(center kml.Coordinate, radius, maxErr float64) []kml.Coordinate {
+	numVertices := sphere.CircleVertices(radius, maxErr)
+	cs := make([]kml.Coordinate, numVertices+1)
+	for i := range numVertices {
+		cs[i] = t.Offset(center, radius, 360*float64(i)/float64(numVertices))
+	}
+	cs[numVertices] = cs[0]
+	return cs
+}
+
+// Distance returns the geodesic distance between c1 and c2. It returns NaN if
+// the calculation does not converge. Altitude is ignored.
+func (t T) Distance(c1, c2 kml.Coordinate) float64 {
+	distance, _, _ := t.inverse(c1, c2)
+	return distance
+}
+
+// FinalBearingTo returns the final bearing on arriving at c2 from c1. It
+// returns NaN if the calculation does not converge. Altitude is ignored.
+func (t T) FinalBearingTo(c1, c2 kml.Coordinate) float64 {
+	_, _, finalBearing := t.inverse(c1, c2)
+	return finalBearing
+}
+
+// InitialBearingTo returns the initial bearing from c1 to c2. It returns NaN
+// if the calculation does not converge. Altitude is ignored.
+func (t T) InitialBearingTo(c1, c2 kml.Coordinate) float64 {
+	_, initialBearing, _ := t.inverse(c1, c2)
+	return initialBearing
+}
+
+// b returns t's semi-minor axis.
+func (t T) b() float64 {
+	return t.A * (1 - t.F)
+}
+
+// inverse solves the inverse geodesic problem between c1 and c2, returning
+// the distance and the initial and final bearings.
+func (t T) inverse(c1, c2 kml.Coordinate) (float64, float64, float64) {
+	b := t.b()
+	l := (c2.Lon - c1.Lon) * radians
+	tanU1 := (1 - t.F) * math.Tan(c1.Lat*radians)
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	tanU2 := (1 - t.F) * math.Tan(c2.Lat*radians)
+	cosU2 := 1 / math.Sqrt(1+tanU2*tanU2)
+	sinU2 := tanU2 * cosU2
+
+	lambda := l
+	var sinLambda, cosLambda, sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	converged := false
+	for range maxIterations {
+		sinLambda, cosLambda = math.Sincos(lambda)
+		sinSqSigma := (cosU2*sinLambda)*(cosU2*sinLambda) + (cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSqSigma == 0 {
+			return 0, 0, 0
+		}
+		sinSigma = math.Sqrt(sinSqSigma)
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		} else {
+			cos2SigmaM = 0
+		}
+		c := t.F / 16 * cosSqAlpha * (4 + t.F*(4-3*cosSqAlpha))
+		prevLambda := lambda
+		lambda = l + (1-c)*t.F*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prevLambda) <= epsilon {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	uSq := cosSqAlpha * (t.A*t.A - b*b) / (b * b)
+	a, bb := vincentyAB(uSq)
+	deltaSigma := vincentyDeltaSigma(bb, sinSigma, cosSigma, cos2SigmaM)
+	distance := b * a * (sigma - deltaSigma)
+	initialBearing := math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda) * degrees
+	finalBearing := math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda) * degrees
+	return distance, initialBearing, finalBearing
+}
+
+// vincentyAB returns Vincenty's A and B coefficients for uSq.
+func vincentyAB(uSq float64) (float64, float64) {
+	a := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	b := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	return a, b
+}
+
+// vincentyDeltaSigma returns Vincenty's delta sigma.
+func vincentyDeltaSigma(b, sinSigma, cosSigma, cos2SigmaM float64) float64 {
+	return b * sinSigma * (cos2SigmaM + b/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-b/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+}
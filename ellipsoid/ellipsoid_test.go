@@ -0,0 +1,64 @@
+package ellipsoid_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/ellipsoid"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+var (
+	_ sphere.Geodesic = ellipsoid.WGS84
+	_ sphere.Geodesic = sphere.WGS84
+)
+
+var (
+	flindersPeak = kml.Coordinate{Lon: dms(144, 25, 29.52440), Lat: -dms(37, 57, 3.72030)}
+	buninyong    = kml.Coordinate{Lon: dms(143, 55, 35.38390), Lat: -dms(37, 39, 10.15610)}
+)
+
+func TestInverse(t *testing.T) {
+	assertInDelta(t, 54972.271, ellipsoid.GRS80.Distance(flindersPeak, buninyong), 1e-3)
+	assertInDelta(t, dms(306, 52, 5.37)-360, ellipsoid.GRS80.InitialBearingTo(flindersPeak, buninyong), 1e-5)
+	assertInDelta(t, dms(307, 10, 25.07)-360, ellipsoid.GRS80.FinalBearingTo(flindersPeak, buninyong), 1e-5)
+	assert.Equal(t, 0, ellipsoid.WGS84.Distance(flindersPeak, flindersPeak))
+}
+
+func TestInverseNotConverging(t *testing.T) {
+	c1 := kml.Coordinate{Lon: 0, Lat: 0}
+	c2 := kml.Coordinate{Lon: 179.7, Lat: 0.5}
+	assert.True(t, math.IsNaN(ellipsoid.WGS84.Distance(c1, c2)))
+}
+
+func TestOffset(t *testing.T) {
+	actual := ellipsoid.GRS80.Offset(flindersPeak, 54972.271, dms(306, 52, 5.37))
+	assertInDelta(t, buninyong.Lon, actual.Lon, 1e-7)
+	assertInDelta(t, buninyong.Lat, actual.Lat, 1e-7)
+}
+
+func TestCircle(t *testing.T) {
+	center := kml.Coordinate{Lon: 7.658320, Lat: 45.97651, Alt: 1000}
+	cs := ellipsoid.WGS84.Circle(center, 5500, 1)
+	assert.Equal(t, 118, len(cs))
+	assert.Equal(t, cs[0], cs[len(cs)-1])
+	for _, c := range cs {
+		assertInDelta(t, 5500, ellipsoid.WGS84.Distance(center, c), 1e-6)
+		assert.Equal(t, center.Alt, c.Alt)
+	}
+}
+
+func assertInDelta(tb testing.TB, expected, actual, delta float64) {
+	tb.Helper()
+	if math.Abs(expected-actual) <= delta {
+		return
+	}
+	tb.Fatalf("Expected %f to be within %f of %f", actual, delta, expected)
+}
+
+func dms(d, m, s float64) float64 {
+	return d + m/60 + s/3600
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/twpayne/go-gpx"
 	kml "github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/ellipsoid"
 	"github.com/twpayne/go-kml/v3/icon"
 	"github.com/twpayne/go-kml/v3/sphere"
 	polyline "github.com/twpayne/go-polyline"
@@ -22,6 +23,8 @@ var (
 	raceFlag   = flag.String("race", "red-bull-x-alps-2025", "race")
 )
 
+var geodesic sphere.Geodesic = ellipsoid.WGS84
+
 var blockBearings = map[string]int{
 	"S":  0,
 	"SW": 45,
@@ -54,12 +57,12 @@ type race struct {
 
 var (
 	berghausBaregg   = kml.Coordinate{Lat: 46.60046, Lon: 8.060011}
-	berghausBareggNW = sphere.FAI.Offset(berghausBaregg, 100, 315)
-	berghausBareggSE = sphere.FAI.Offset(berghausBaregg, 100, 135)
+	berghausBareggNW = geodesic.Offset(berghausBaregg, 100, 315)
+	berghausBareggSE = geodesic.Offset(berghausBaregg, 100, 135)
 
 	lobhornhutte   = kml.Coordinate{Lat: 46.618514, Lon: 7.868981}
-	lobhornhutteNW = sphere.FAI.Offset(lobhornhutte, 100, 315)
-	lobhornhutteSE = sphere.FAI.Offset(lobhornhutte, 100, 135)
+	lobhornhutteNW = geodesic.Offset(lobhornhutte, 100, 315)
+	lobhornhutteSE = geodesic.Offset(lobhornhutte, 100, 135)
 )
 
 var races = map[string]race{
@@ -866,7 +869,7 @@ func (tp turnpoint) kmlFolder() kml.Element {
 	if tp.radius != 0 {
 		radiusPlacemark = kml.Placemark(
 			kml.LineString(
-				kml.Coordinates(sphere.FAI.Circle(center, float64(tp.radius), 1)...),
+				kml.Coordinates(geodesic.Circle(center, float64(tp.radius), 1)...),
 				kml.Tessellate(true),
 			),
 			kml.Style(
@@ -884,7 +887,7 @@ func (tp turnpoint) kmlFolder() kml.Element {
 				kml.LineString(
 					kml.Coordinates(
 						center,
-						sphere.FAI.Offset(center, 25000, float64(blockBearing)),
+						geodesic.Offset(center, 25000, float64(blockBearing)),
 					),
 					kml.Tessellate(true),
 				),
@@ -0,0 +1,205 @@
+package kml
+
+import (
+	"math"
+	"time"
+)
+
+// A Bounds is a geographic bounding box. Min contains the west, south, and
+// minimum altitude and Max contains the east, north, and maximum altitude.
+// Bounds do not take the antimeridian into account. The altitudes are +Inf
+// and -Inf if the box has no altitude range, as when it only covers
+// two-dimensional coordinates.
+type Bounds struct {
+	Min Coordinate
+	Max Coordinate
+}
+
+// An ExtentInfo contains the spatial and temporal extent of an element tree
+// and the number of features and geometries that it contains. Geometries
+// counts only leaf geometries: MultiGeometries and gx:MultiTracks are not
+// counted themselves, and the LinearRings of a Polygon are part of the
+// Polygon.
+type ExtentInfo struct {
+	Bounds     Bounds
+	Begin      time.Time
+	End        time.Time
+	Features   int
+	Geometries int
+}
+
+// EmptyBounds returns a new empty Bounds.
+func EmptyBounds() Bounds {
+	return Bounds{
+		Min: Coordinate{Lon: math.Inf(1), Lat: math.Inf(1), Alt: math.Inf(1)},
+		Max: Coordinate{Lon: math.Inf(-1), Lat: math.Inf(-1), Alt: math.Inf(-1)},
+	}
+}
+
+// Extend returns b extended to include c.
+func (b Bounds) Extend(c Coordinate) Bounds {
+	return Bounds{
+		Min: Coordinate{Lon: min(b.Min.Lon, c.Lon), Lat: min(b.Min.Lat, c.Lat), Alt: min(b.Min.Alt, c.Alt)},
+		Max: Coordinate{Lon: max(b.Max.Lon, c.Lon), Lat: max(b.Max.Lat, c.Lat), Alt: max(b.Max.Alt, c.Alt)},
+	}
+}
+
+// HasAlt returns if b has an altitude range.
+func (b Bounds) HasAlt() bool {
+	return b.Min.Alt <= b.Max.Alt
+}
+
+// IsEmpty returns if b is empty.
+func (b Bounds) IsEmpty() bool {
+	return b.Min.Lon > b.Max.Lon || b.Min.Lat > b.Max.Lat
+}
+
+// LatLonAltBox returns a new LatLonAltBoxElement covering b, with additional
+// children. The minAltitude and maxAltitude are omitted if b has no altitude
+// range.
+func (b Bounds) LatLonAltBox(children ...Element) *LatLonAltBoxElement {
+	latLonAltBox := LatLonAltBox(
+		North(b.Max.Lat),
+		South(b.Min.Lat),
+		East(b.Max.Lon),
+		West(b.Min.Lon),
+	)
+	if b.HasAlt() {
+		latLonAltBox.Append(
+			MinAltitude(b.Min.Alt),
+			MaxAltitude(b.Max.Alt),
+		)
+	}
+	return latLonAltBox.Append(children...)
+}
+
+// CoordinatesOf returns the coordinates of element and their dimension, 2 or
+// 3, if element is a CoordinatesElement, CoordinatesFlatElement, or
+// CoordinatesSliceElement. Otherwise it returns nil and 0. Coordinates without
+// an altitude have an Alt of zero. A CoordinatesElement has dimension 3 if any
+// of its coordinates has a non-zero altitude, as only then are altitudes
+// written.
+func CoordinatesOf(element Element) ([]Coordinate, int) {
+	switch element := element.(type) {
+	case CoordinatesElement:
+		dim := 2
+		for _, c := range element {
+			if c.Alt != 0 {
+				dim = 3
+				break
+			}
+		}
+		return element, dim
+	case *CoordinatesFlatElement:
+		cs := make([]Coordinate, 0, (element.End-element.Offset)/element.Stride)
+		for i := element.Offset; i < element.End; i += element.Stride {
+			c := Coordinate{Lon: element.FlatCoords[i], Lat: element.FlatCoords[i+1]}
+			if element.Dim > 2 {
+				c.Alt = element.FlatCoords[i+2]
+			}
+			cs = append(cs, c)
+		}
+		return cs, min(element.Dim, 3)
+	case CoordinatesSliceElement:
+		dim := 2
+		cs := make([]Coordinate, 0, len(element))
+		for _, s := range element {
+			c := Coordinate{Lon: s[0], Lat: s[1]}
+			if len(s) > 2 {
+				c.Alt = s[2]
+				dim = 3
+			}
+			cs = append(cs, c)
+		}
+		return cs, dim
+	default:
+		return nil, 0
+	}
+}
+
+// Extent returns the spatial and temporal extent of the tree rooted at
+// element. The spatial extent includes all coordinates, gx:coords, LatLonBoxes,
+// and gx:LatLonQuads. Only three-dimensional coordinates and gx:coords extend
+// the altitude range. The temporal extent includes all whens, begins, and
+// ends. The Begin and End of the returned ExtentInfo are zero if the tree
+// contains no times.
+func Extent(element Element) ExtentInfo {
+	extentInfo := ExtentInfo{
+		Bounds: EmptyBounds(),
+	}
+	extendTime := func(t time.Time) {
+		if extentInfo.Begin.IsZero() || t.Before(extentInfo.Begin) {
+			extentInfo.Begin = t
+		}
+		if extentInfo.End.IsZero() || t.After(extentInfo.End) {
+			extentInfo.End = t
+		}
+	}
+	extendCoordinates := func(element Element) error {
+		cs, dim := CoordinatesOf(element)
+		for _, c := range cs {
+			if dim > 2 {
+				extentInfo.Bounds = extentInfo.Bounds.Extend(c)
+			} else {
+				extentInfo.Bounds = extentInfo.Bounds.extendLonLat(c)
+			}
+		}
+		return nil
+	}
+	_ = Walk(element, func(element Element) error {
+		switch element := element.(type) {
+		case CoordinatesElement, *CoordinatesFlatElement, CoordinatesSliceElement:
+			return extendCoordinates(element)
+		case GxCoordElement:
+			extentInfo.Bounds = extentInfo.Bounds.Extend(Coordinate(element))
+		case *LatLonBoxElement:
+			extentInfo.Bounds = extentInfo.Bounds.extendLatLonBox(element)
+			return SkipChildren
+		case *WhenElement:
+			extendTime(element.Value)
+		case *BeginElement:
+			extendTime(element.Value)
+		case *EndElement:
+			extendTime(element.Value)
+		case *DocumentElement, *FolderElement, *GroundOverlayElement, *GxTourElement, *NetworkLinkElement, *PhotoOverlayElement, *PlacemarkElement, *ScreenOverlayElement:
+			extentInfo.Features++
+		case *PolygonElement:
+			extentInfo.Geometries++
+			_ = Walk(element, extendCoordinates)
+			return SkipChildren
+		case *GxTrackElement, *LineStringElement, *LinearRingElement, *ModelElement, *PointElement:
+			extentInfo.Geometries++
+		}
+		return nil
+	})
+	return extentInfo
+}
+
+// extendLonLat returns b extended to include the longitude and latitude of c.
+func (b Bounds) extendLonLat(c Coordinate) Bounds {
+	b.Min.Lon, b.Min.Lat = min(b.Min.Lon, c.Lon), min(b.Min.Lat, c.Lat)
+	b.Max.Lon, b.Max.Lat = max(b.Max.Lon, c.Lon), max(b.Max.Lat, c.Lat)
+	return b
+}
+
+// extendLatLonBox returns b extended to include the north, south, east, and
+// west children of latLonBox.
+func (b Bounds) extendLatLonBox(latLonBox *LatLonBoxElement) Bounds {
+	for _, child := range latLonBox.Children {
+		switch child := child.(type) {
+		case *NorthElement:
+			b.Max.Lat = max(b.Max.Lat, child.Value)
+			b.Min.Lat = min(b.Min.Lat, child.Value)
+		case *SouthElement:
+			b.Max.Lat = max(b.Max.Lat, child.Value)
+			b.Min.Lat = min(b.Min.Lat, child.Value)
+		case *EastElement:
+			b.Max.Lon = max(b.Max.Lon, child.Value)
+			b.Min.Lon = min(b.Min.Lon, child.Value)
+		case *WestElement:
+			b.Max.Lon = max(b.Max.Lon, child.Value)
+			b.Min.Lon = min(b.Min.Lon, child.Value)
+		}
+	}
+	return b
+}
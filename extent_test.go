@@ -0,0 +1,223 @@
+package kml_test
+
+import (
+	"encoding/xml"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+func TestExtent(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		element  kml.Element
+		expected kml.ExtentInfo
+	}{
+		{
+			name:    "empty",
+			element: kml.KML(kml.Document()),
+			expected: kml.ExtentInfo{
+				Bounds:   kml.EmptyBounds(),
+				Features: 1,
+			},
+		},
+		{
+			name: "placemarks",
+			element: kml.GxKML(
+				kml.Folder(
+					kml.Placemark(
+						kml.TimeStamp(kml.When(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))),
+						kml.Point(kml.Coordinates(kml.Coordinate{Lon: 1, Lat: 2, Alt: 3})),
+					),
+					kml.Placemark(
+						kml.TimeSpan(
+							kml.Begin(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+							kml.End(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)),
+						),
+						kml.MultiGeometry(
+							kml.LineString(kml.CoordinatesFlat([]float64{-1, 5, 10, 4, 6, 20}, 0, 6, 3, 3)),
+							kml.LineString(kml.CoordinatesSlice([]float64{7, -2})),
+						),
+					),
+					kml.Placemark(
+						kml.GxTrack(
+							kml.When(time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)),
+							kml.GxCoord(kml.Coordinate{Lon: 0, Lat: 0, Alt: -10}),
+						),
+					),
+				),
+			),
+			expected: kml.ExtentInfo{
+				Bounds: kml.Bounds{
+					Min: kml.Coordinate{Lon: -1, Lat: -2, Alt: -10},
+					Max: kml.Coordinate{Lon: 7, Lat: 6, Alt: 20},
+				},
+				Begin:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				End:        time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+				Features:   4,
+				Geometries: 4,
+			},
+		},
+		{
+			name: "overlays",
+			element: kml.Document(
+				kml.GroundOverlay(
+					kml.LatLonBox(
+						kml.North(10),
+						kml.South(-10),
+						kml.East(20),
+						kml.West(-20),
+					),
+				),
+				kml.GroundOverlay(
+					kml.GxLatLonQuad(
+						kml.Coordinates(
+							kml.Coordinate{Lon: 30, Lat: 0},
+							kml.Coordinate{Lon: 31, Lat: 0},
+							kml.Coordinate{Lon: 31, Lat: 1},
+							kml.Coordinate{Lon: 30, Lat: 1},
+						),
+					),
+				),
+			),
+			expected: kml.ExtentInfo{
+				Bounds: kml.Bounds{
+					Min: kml.Coordinate{Lon: -20, Lat: -10, Alt: math.Inf(1)},
+					Max: kml.Coordinate{Lon: 31, Lat: 10, Alt: math.Inf(-1)},
+				},
+				Features: 3,
+			},
+		},
+		{
+			name: "clamped",
+			element: kml.MultiGeometry(
+				kml.LineString(kml.CoordinatesFlat([]float64{0, 0, 1, 1}, 0, 4, 2, 2)),
+				kml.LineString(kml.CoordinatesSlice([]float64{2, 2})),
+				kml.LineString(kml.Coordinates(kml.Coordinate{Lon: 3, Lat: 3})),
+				kml.Point(kml.Coordinates(kml.Coordinate{Lon: 4, Lat: 4, Alt: 100})),
+			),
+			expected: kml.ExtentInfo{
+				Bounds: kml.Bounds{
+					Min: kml.Coordinate{Lon: 0, Lat: 0, Alt: 100},
+					Max: kml.Coordinate{Lon: 4, Lat: 4, Alt: 100},
+				},
+				Geometries: 4,
+			},
+		},
+		{
+			name: "leaf_geometries",
+			element: kml.MultiGeometry(
+				kml.Polygon(
+					kml.OuterBoundaryIs(kml.LinearRing(kml.Coordinates(
+						kml.Coordinate{Lon: 0, Lat: 0}, kml.Coordinate{Lon: 2, Lat: 0}, kml.Coordinate{Lon: 2, Lat: 2}, kml.Coordinate{Lon: 0, Lat: 0},
+					))),
+					kml.InnerBoundaryIs(kml.LinearRing(kml.Coordinates(
+						kml.Coordinate{Lon: 1, Lat: 0.5}, kml.Coordinate{Lon: 1.5, Lat: 1}, kml.Coordinate{Lon: 1.5, Lat: 0.5}, kml.Coordinate{Lon: 1, Lat: 0.5},
+					))),
+				),
+				kml.GxMultiTrack(
+					kml.GxTrack(kml.GxCoord(kml.Coordinate{Lon: 3, Lat: 3, Alt: 1})),
+					kml.GxTrack(kml.GxCoord(kml.Coordinate{Lon: 4, Lat: 4, Alt: 2})),
+				),
+			),
+			expected: kml.ExtentInfo{
+				Bounds: kml.Bounds{
+					Min: kml.Coordinate{Lon: 0, Lat: 0, Alt: 1},
+					Max: kml.Coordinate{Lon: 4, Lat: 4, Alt: 2},
+				},
+				Geometries: 3,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, kml.Extent(tc.element))
+		})
+	}
+}
+
+func TestBoundsLatLonAltBox(t *testing.T) {
+	bounds := kml.Extent(kml.GroundOverlay(
+		kml.LatLonBox(kml.North(1), kml.South(-1), kml.East(2), kml.West(-2)),
+	)).Bounds
+	assert.False(t, bounds.HasAlt())
+	actual, err := xml.Marshal(bounds.LatLonAltBox())
+	assert.NoError(t, err)
+	assert.Equal(t, `<LatLonAltBox><north>1</north><south>-1</south><east>2</east><west>-2</west></LatLonAltBox>`, string(actual))
+
+	bounds = bounds.Extend(kml.Coordinate{Alt: 10})
+	assert.True(t, bounds.HasAlt())
+	actual, err = xml.Marshal(bounds.LatLonAltBox(kml.AltitudeMode(kml.AltitudeModeAbsolute)))
+	assert.NoError(t, err)
+	assert.Equal(t, `<LatLonAltBox><north>1</north><south>-1</south><east>2</east><west>-2</west><minAltitude>10</minAltitude><maxAltitude>10</maxAltitude><altitudeMode>absolute</altitudeMode></LatLonAltBox>`, string(actual))
+}
+
+func TestCoordinatesOf(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		element     kml.Element
+		expected    []kml.Coordinate
+		expectedDim int
+	}{
+		{
+			name:        "coordinates_2d",
+			element:     kml.Coordinates(kml.Coordinate{Lon: 1, Lat: 2}),
+			expected:    []kml.Coordinate{{Lon: 1, Lat: 2}},
+			expectedDim: 2,
+		},
+		{
+			name:        "coordinates_3d",
+			element:     kml.Coordinates(kml.Coordinate{Lon: 1, Lat: 2}, kml.Coordinate{Lon: 3, Lat: 4, Alt: 5}),
+			expected:    []kml.Coordinate{{Lon: 1, Lat: 2}, {Lon: 3, Lat: 4, Alt: 5}},
+			expectedDim: 3,
+		},
+		{
+			name:        "flat_2d",
+			element:     kml.CoordinatesFlat([]float64{0, 0, 1, 2, 3, 4}, 2, 6, 2, 2),
+			expected:    []kml.Coordinate{{Lon: 1, Lat: 2}, {Lon: 3, Lat: 4}},
+			expectedDim: 2,
+		},
+		{
+			name:        "flat_4d",
+			element:     kml.CoordinatesFlat([]float64{1, 2, 3, 4}, 0, 4, 4, 4),
+			expected:    []kml.Coordinate{{Lon: 1, Lat: 2, Alt: 3}},
+			expectedDim: 3,
+		},
+		{
+			name:        "slice",
+			element:     kml.CoordinatesSlice([]float64{1, 2}, []float64{3, 4, 5}),
+			expected:    []kml.Coordinate{{Lon: 1, Lat: 2}, {Lon: 3, Lat: 4, Alt: 5}},
+			expectedDim: 3,
+		},
+		{
+			name:    "other",
+			element: kml.Point(),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, actualDim := kml.CoordinatesOf(tc.element)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.expectedDim, actualDim)
+		})
+	}
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	var names []string
+	assert.NoError(t, kml.Walk(kml.Document(
+		kml.Folder(kml.Name("skipped")),
+		kml.Name("visited"),
+	), func(element kml.Element) error {
+		switch element := element.(type) {
+		case *kml.FolderElement:
+			return kml.SkipChildren
+		case *kml.NameElement:
+			names = append(names, element.Value)
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"visited"}, names)
+}
@@ -0,0 +1,576 @@
+package icon
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// Hot spots of catalog icons.
+var (
+	centerHotSpot  = kml.Vec2{X: 0.5, Y: 0.5, XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction}
+	paddleHotSpot  = kml.Vec2{X: 0.5, Y: 0, XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction}
+	pushpinHotSpot = kml.Vec2{X: 20, Y: 2, XUnits: kml.UnitsPixels, YUnits: kml.UnitsPixels}
+)
+
+// A PushpinIcon is a pushpin icon, identified by its color. See
+// http://kml4earth.appspot.com/icons.html#pushpin.
+type PushpinIcon string
+
+// Pushpin icons.
+const (
+	PushpinBlue      PushpinIcon = "blue"
+	PushpinGreen     PushpinIcon = "grn"
+	PushpinLightBlue PushpinIcon = "ltblu"
+	PushpinPink      PushpinIcon = "pink"
+	PushpinPurple    PushpinIcon = "purple"
+	PushpinRed       PushpinIcon = "red"
+	PushpinWhite     PushpinIcon = "wht"
+	PushpinYellow    PushpinIcon = "ylw"
+)
+
+// A PaddleIcon is a paddle icon, identified by its id. See
+// http://kml4earth.appspot.com/icons.html#paddle.
+type PaddleIcon string
+
+// Paddle icons that are not colored shapes, letters, or numbers.
+const (
+	PaddleGo    PaddleIcon = "go"
+	PaddlePause PaddleIcon = "pause"
+	PaddleStop  PaddleIcon = "stop"
+)
+
+// A PaddleColor is the color of a paddle icon.
+type PaddleColor string
+
+// Paddle colors.
+const (
+	PaddleBlue      PaddleColor = "blu"
+	PaddleGreen     PaddleColor = "grn"
+	PaddleLightBlue PaddleColor = "ltblu"
+	PaddlePink      PaddleColor = "pink"
+	PaddlePurple    PaddleColor = "purple"
+	PaddleRed       PaddleColor = "red"
+	PaddleWhite     PaddleColor = "wht"
+	PaddleYellow    PaddleColor = "ylw"
+)
+
+// A PaddleShape is the shape drawn on a paddle icon.
+type PaddleShape string
+
+// Paddle shapes.
+const (
+	PaddleBlank   PaddleShape = "blank"
+	PaddleCircle  PaddleShape = "circle"
+	PaddleDiamond PaddleShape = "diamond"
+	PaddleSquare  PaddleShape = "square"
+	PaddleStars   PaddleShape = "stars"
+)
+
+// A ShapeIcon is a shape icon, identified by its name. See
+// http://kml4earth.appspot.com/icons.html#shapes.
+type ShapeIcon string
+
+// Shape icons.
+const (
+	ShapeAirports                 ShapeIcon = "airports"
+	ShapeArrow                    ShapeIcon = "arrow"
+	ShapeArrowReverse             ShapeIcon = "arrow-reverse"
+	ShapeArts                     ShapeIcon = "arts"
+	ShapeBars                     ShapeIcon = "bars"
+	ShapeBrokenLink               ShapeIcon = "broken_link"
+	ShapeBus                      ShapeIcon = "bus"
+	ShapeCabs                     ShapeIcon = "cabs"
+	ShapeCamera                   ShapeIcon = "camera"
+	ShapeCampfire                 ShapeIcon = "campfire"
+	ShapeCampground               ShapeIcon = "campground"
+	ShapeCapitalBig               ShapeIcon = "capital_big"
+	ShapeCapitalBigHighlight      ShapeIcon = "capital_big_highlight"
+	ShapeCapitalSmall             ShapeIcon = "capital_small"
+	ShapeCapitalSmallHighlight    ShapeIcon = "capital_small_highlight"
+	ShapeCaution                  ShapeIcon = "caution"
+	ShapeCoffee                   ShapeIcon = "coffee"
+	ShapeConvenience              ShapeIcon = "convenience"
+	ShapeCrossHairs               ShapeIcon = "cross-hairs"
+	ShapeCrossHairsHighlight      ShapeIcon = "cross-hairs_highlight"
+	ShapeCycling                  ShapeIcon = "cycling"
+	ShapeDining                   ShapeIcon = "dining"
+	ShapeDollar                   ShapeIcon = "dollar"
+	ShapeDonut                    ShapeIcon = "donut"
+	ShapeEarthquake               ShapeIcon = "earthquake"
+	ShapeElectronics              ShapeIcon = "electronics"
+	ShapeEuro                     ShapeIcon = "euro"
+	ShapeFallingRocks             ShapeIcon = "falling_rocks"
+	ShapeFerry                    ShapeIcon = "ferry"
+	ShapeFiredept                 ShapeIcon = "firedept"
+	ShapeFishing                  ShapeIcon = "fishing"
+	ShapeFlag                     ShapeIcon = "flag"
+	ShapeForbidden                ShapeIcon = "forbidden"
+	ShapeGasStations              ShapeIcon = "gas_stations"
+	ShapeGolf                     ShapeIcon = "golf"
+	ShapeGrocery                  ShapeIcon = "grocery"
+	ShapeHeliport                 ShapeIcon = "heliport"
+	ShapeHiker                    ShapeIcon = "hiker"
+	ShapeHomegardenbusiness       ShapeIcon = "homegardenbusiness"
+	ShapeHorsebackriding          ShapeIcon = "horsebackriding"
+	ShapeHospitals                ShapeIcon = "hospitals"
+	ShapeInfo                     ShapeIcon = "info"
+	ShapeInfoI                    ShapeIcon = "info-i"
+	ShapeInfoCircle               ShapeIcon = "info_circle"
+	ShapeLodging                  ShapeIcon = "lodging"
+	ShapeMan                      ShapeIcon = "man"
+	ShapeMarina                   ShapeIcon = "marina"
+	ShapeMechanic                 ShapeIcon = "mechanic"
+	ShapeMotorcycling             ShapeIcon = "motorcycling"
+	ShapeMountains                ShapeIcon = "mountains"
+	ShapeMovies                   ShapeIcon = "movies"
+	ShapeOpenDiamond              ShapeIcon = "open-diamond"
+	ShapeParkingLot               ShapeIcon = "parking_lot"
+	ShapeParks                    ShapeIcon = "parks"
+	ShapePartlyCloudy             ShapeIcon = "partly_cloudy"
+	ShapePharmacyRx               ShapeIcon = "pharmacy_rx"
+	ShapePhone                    ShapeIcon = "phone"
+	ShapePicnic                   ShapeIcon = "picnic"
+	ShapePlacemarkCircle          ShapeIcon = "placemark_circle"
+	ShapePlacemarkCircleHighlight ShapeIcon = "placemark_circle_highlight"
+	ShapePlacemarkSquare          ShapeIcon = "placemark_square"
+	ShapePlacemarkSquareHighlight ShapeIcon = "placemark_square_highlight"
+	ShapePlay                     ShapeIcon = "play"
+	ShapePoi                      ShapeIcon = "poi"
+	ShapePolice                   ShapeIcon = "police"
+	ShapePolygon                  ShapeIcon = "polygon"
+	ShapePostOffice               ShapeIcon = "post_office"
+	ShapeRail                     ShapeIcon = "rail"
+	ShapeRainy                    ShapeIcon = "rainy"
+	ShapeRangerStation            ShapeIcon = "ranger_station"
+	ShapeRealestate               ShapeIcon = "realestate"
+	ShapeRoadShield1              ShapeIcon = "road_shield1"
+	ShapeRoadShield2              ShapeIcon = "road_shield2"
+	ShapeRoadShield3              ShapeIcon = "road_shield3"
+	ShapeSailing                  ShapeIcon = "sailing"
+	ShapeSalon                    ShapeIcon = "salon"
+	ShapeSchools                  ShapeIcon = "schools"
+	ShapeShadedDot                ShapeIcon = "shaded_dot"
+	ShapeShopping                 ShapeIcon = "shopping"
+	ShapeSki                      ShapeIcon = "ski"
+	ShapeSnackBar                 ShapeIcon = "snack_bar"
+	ShapeSnowflakeSimple          ShapeIcon = "snowflake_simple"
+	ShapeSquare                   ShapeIcon = "square"
+	ShapeStar                     ShapeIcon = "star"
+	ShapeSubway                   ShapeIcon = "subway"
+	ShapeSunny                    ShapeIcon = "sunny"
+	ShapeSwimming                 ShapeIcon = "swimming"
+	ShapeTarget                   ShapeIcon = "target"
+	ShapeThunderstorm             ShapeIcon = "thunderstorm"
+	ShapeToilets                  ShapeIcon = "toilets"
+	ShapeTrail                    ShapeIcon = "trail"
+	ShapeTram                     ShapeIcon = "tram"
+	ShapeTriangle                 ShapeIcon = "triangle"
+	ShapeTruck                    ShapeIcon = "truck"
+	ShapeVolcano                  ShapeIcon = "volcano"
+	ShapeWater                    ShapeIcon = "water"
+	ShapeWebcam                   ShapeIcon = "webcam"
+	ShapeWheelChairAccessible     ShapeIcon = "wheel_chair_accessible"
+	ShapeWoman                    ShapeIcon = "woman"
+	ShapeYen                      ShapeIcon = "yen"
+)
+
+// A PaletteIcon is an icon in one of the palettes pal2 to pal5, identified by
+// its palette and its index from 0 to 63. See
+// http://kml4earth.appspot.com/icons.html#pal2.
+type PaletteIcon struct {
+	Palette int
+	Index   int
+}
+
+// An Entry is an entry in the catalog of standard icons. Exactly one of its
+// fields is set.
+type Entry struct {
+	Pushpin PushpinIcon
+	Paddle  PaddleIcon
+	Shape   ShapeIcon
+	Palette PaletteIcon
+}
+
+var (
+	pushpinIcons = map[PushpinIcon]struct{}{
+		PushpinBlue:      {},
+		PushpinGreen:     {},
+		PushpinLightBlue: {},
+		PushpinPink:      {},
+		PushpinPurple:    {},
+		PushpinRed:       {},
+		PushpinWhite:     {},
+		PushpinYellow:    {},
+	}
+	paddleColors = map[PaddleColor]struct{}{
+		PaddleBlue:      {},
+		PaddleGreen:     {},
+		PaddleLightBlue: {},
+		PaddlePink:      {},
+		PaddlePurple:    {},
+		PaddleRed:       {},
+		PaddleWhite:     {},
+		PaddleYellow:    {},
+	}
+	paddleShapes = map[PaddleShape]struct{}{
+		PaddleBlank:   {},
+		PaddleCircle:  {},
+		PaddleDiamond: {},
+		PaddleSquare:  {},
+		PaddleStars:   {},
+	}
+	shapeIcons = map[ShapeIcon]struct{}{
+		ShapeAirports:                 {},
+		ShapeArrow:                    {},
+		ShapeArrowReverse:             {},
+		ShapeArts:                     {},
+		ShapeBars:                     {},
+		ShapeBrokenLink:               {},
+		ShapeBus:                      {},
+		ShapeCabs:                     {},
+		ShapeCamera:                   {},
+		ShapeCampfire:                 {},
+		ShapeCampground:               {},
+		ShapeCapitalBig:               {},
+		ShapeCapitalBigHighlight:      {},
+		ShapeCapitalSmall:             {},
+		ShapeCapitalSmallHighlight:    {},
+		ShapeCaution:                  {},
+		ShapeCoffee:                   {},
+		ShapeConvenience:              {},
+		ShapeCrossHairs:               {},
+		ShapeCrossHairsHighlight:      {},
+		ShapeCycling:                  {},
+		ShapeDining:                   {},
+		ShapeDollar:                   {},
+		ShapeDonut:                    {},
+		ShapeEarthquake:               {},
+		ShapeElectronics:              {},
+		ShapeEuro:                     {},
+		ShapeFallingRocks:             {},
+		ShapeFerry:                    {},
+		ShapeFiredept:                 {},
+		ShapeFishing:                  {},
+		ShapeFlag:                     {},
+		ShapeForbidden:                {},
+		ShapeGasStations:              {},
+		ShapeGolf:                     {},
+		ShapeGrocery:                  {},
+		ShapeHeliport:                 {},
+		ShapeHiker:                    {},
+		ShapeHomegardenbusiness:       {},
+		ShapeHorsebackriding:          {},
+		ShapeHospitals:                {},
+		ShapeInfo:                     {},
+		ShapeInfoI:                    {},
+		ShapeInfoCircle:               {},
+		ShapeLodging:                  {},
+		ShapeMan:                      {},
+		ShapeMarina:                   {},
+		ShapeMechanic:                 {},
+		ShapeMotorcycling:             {},
+		ShapeMountains:                {},
+		ShapeMovies:                   {},
+		ShapeOpenDiamond:              {},
+		ShapeParkingLot:               {},
+		ShapeParks:                    {},
+		ShapePartlyCloudy:             {},
+		ShapePharmacyRx:               {},
+		ShapePhone:                    {},
+		ShapePicnic:                   {},
+		ShapePlacemarkCircle:          {},
+		ShapePlacemarkCircleHighlight: {},
+		ShapePlacemarkSquare:          {},
+		ShapePlacemarkSquareHighlight: {},
+		ShapePlay:                     {},
+		ShapePoi:                      {},
+		ShapePolice:                   {},
+		ShapePolygon:                  {},
+		ShapePostOffice:               {},
+		ShapeRail:                     {},
+		ShapeRainy:                    {},
+		ShapeRangerStation:            {},
+		ShapeRealestate:               {},
+		ShapeRoadShield1:              {},
+		ShapeRoadShield2:              {},
+		ShapeRoadShield3:              {},
+		ShapeSailing:                  {},
+		ShapeSalon:                    {},
+		ShapeSchools:                  {},
+		ShapeShadedDot:                {},
+		ShapeShopping:                 {},
+		ShapeSki:                      {},
+		ShapeSnackBar:                 {},
+		ShapeSnowflakeSimple:          {},
+		ShapeSquare:                   {},
+		ShapeStar:                     {},
+		ShapeSubway:                   {},
+		ShapeSunny:                    {},
+		ShapeSwimming:                 {},
+		ShapeTarget:                   {},
+		ShapeThunderstorm:             {},
+		ShapeToilets:                  {},
+		ShapeTrail:                    {},
+		ShapeTram:                     {},
+		ShapeTriangle:                 {},
+		ShapeTruck:                    {},
+		ShapeVolcano:                  {},
+		ShapeWater:                    {},
+		ShapeWebcam:                   {},
+		ShapeWheelChairAccessible:     {},
+		ShapeWoman:                    {},
+		ShapeYen:                      {},
+	}
+)
+
+// ColoredPaddle returns the paddle icon with color and shape.
+func ColoredPaddle(color PaddleColor, shape PaddleShape) PaddleIcon {
+	return PaddleIcon(string(color) + "-" + string(shape))
+}
+
+// Lookup returns the catalog entry of the icon at href and true, or false if
+// href is not the href of an icon in the catalog. Both http and https hrefs
+// are recognized.
+func Lookup(href string) (Entry, bool) {
+	path, ok := strings.CutPrefix(href, "https://")
+	if !ok {
+		path, ok = strings.CutPrefix(href, "http://")
+	}
+	if !ok {
+		return Entry{}, false
+	}
+	path, ok = strings.CutPrefix(path, "maps.google.com/mapfiles/kml/")
+	if !ok {
+		return Entry{}, false
+	}
+	dir, name, ok := strings.Cut(path, "/")
+	if !ok {
+		return Entry{}, false
+	}
+	name, ok = strings.CutSuffix(name, ".png")
+	if !ok {
+		return Entry{}, false
+	}
+	var entry Entry
+	switch dir {
+	case "paddle":
+		entry.Paddle = PaddleIcon(name)
+	case "pushpin":
+		color, ok := strings.CutSuffix(name, "-pushpin")
+		if !ok {
+			return Entry{}, false
+		}
+		entry.Pushpin = PushpinIcon(color)
+	case "shapes":
+		entry.Shape = ShapeIcon(name)
+	default:
+		palette, ok := strings.CutPrefix(dir, "pal")
+		if !ok {
+			return Entry{}, false
+		}
+		index, ok := strings.CutPrefix(name, "icon")
+		if !ok {
+			return Entry{}, false
+		}
+		var err1, err2 error
+		entry.Palette.Palette, err1 = strconv.Atoi(palette)
+		entry.Palette.Index, err2 = strconv.Atoi(index)
+		if err1 != nil || err2 != nil {
+			return Entry{}, false
+		}
+	}
+	if !entry.Valid() {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// PaddleCharacter returns the paddle icon with character c, or an empty
+// PaddleIcon if no such icon exists. Characters are 1 to 9 and A to Z.
+func PaddleCharacter(c rune) PaddleIcon {
+	if '1' <= c && c <= '9' || 'A' <= c && c <= 'Z' {
+		return PaddleIcon(string(c))
+	}
+	return ""
+}
+
+// PaddleNumber returns the paddle icon with number n, or an empty PaddleIcon
+// if no such icon exists. Numbers are 1 to 10.
+func PaddleNumber(n int) PaddleIcon {
+	if 1 <= n && n <= 10 {
+		return PaddleIcon(strconv.Itoa(n))
+	}
+	return ""
+}
+
+// HotSpot returns the hot spot of e.
+func (e Entry) HotSpot() kml.Vec2 {
+	switch {
+	case e.Pushpin != "":
+		return e.Pushpin.HotSpot()
+	case e.Paddle != "":
+		return e.Paddle.HotSpot()
+	case e.Shape != "":
+		return e.Shape.HotSpot()
+	default:
+		return e.Palette.HotSpot()
+	}
+}
+
+// Href returns the href of e.
+func (e Entry) Href() string {
+	switch {
+	case e.Pushpin != "":
+		return e.Pushpin.Href()
+	case e.Paddle != "":
+		return e.Paddle.Href()
+	case e.Shape != "":
+		return e.Shape.Href()
+	default:
+		return e.Palette.Href()
+	}
+}
+
+// IconStyle returns an IconStyle for e with the hot spot set and the given
+// children.
+func (e Entry) IconStyle(children ...kml.Element) *kml.IconStyleElement {
+	return iconStyle(e.Href(), e.HotSpot(), children)
+}
+
+// Valid returns if exactly one field of e is set and is valid.
+func (e Entry) Valid() bool {
+	n := 0
+	valid := true
+	if e.Pushpin != "" {
+		n++
+		valid = valid && e.Pushpin.Valid()
+	}
+	if e.Paddle != "" {
+		n++
+		valid = valid && e.Paddle.Valid()
+	}
+	if e.Shape != "" {
+		n++
+		valid = valid && e.Shape.Valid()
+	}
+	if e.Palette != (PaletteIcon{}) {
+		n++
+		valid = valid && e.Palette.Valid()
+	}
+	return n == 1 && valid
+}
+
+// HotSpot returns the hot spot of p, the tip of the paddle.
+func (p PaddleIcon) HotSpot() kml.Vec2 {
+	return paddleHotSpot
+}
+
+// Href returns the href of p.
+func (p PaddleIcon) Href() string {
+	return PaddleHref(string(p))
+}
+
+// IconStyle returns an IconStyle for p with the hot spot set and the given
+// children.
+func (p PaddleIcon) IconStyle(children ...kml.Element) *kml.IconStyleElement {
+	return iconStyle(p.Href(), p.HotSpot(), children)
+}
+
+// Valid returns if p is a paddle icon.
+func (p PaddleIcon) Valid() bool {
+	switch p {
+	case PaddleGo, PaddlePause, PaddleStop:
+		return true
+	}
+	if len(p) == 1 && PaddleCharacter(rune(p[0])) == p {
+		return true
+	}
+	if n, err := strconv.Atoi(string(p)); err == nil && PaddleNumber(n) == p {
+		return true
+	}
+	color, shape, ok := strings.Cut(string(p), "-")
+	if !ok {
+		return false
+	}
+	_, validColor := paddleColors[PaddleColor(color)]
+	_, validShape := paddleShapes[PaddleShape(shape)]
+	return validColor && validShape
+}
+
+// HotSpot returns the hot spot of p, the center of the icon.
+func (p PaletteIcon) HotSpot() kml.Vec2 {
+	return centerHotSpot
+}
+
+// Href returns the href of p.
+func (p PaletteIcon) Href() string {
+	return PaletteHref(p.Palette, p.Index)
+}
+
+// IconStyle returns an IconStyle for p with the hot spot set and the given
+// children.
+func (p PaletteIcon) IconStyle(children ...kml.Element) *kml.IconStyleElement {
+	return iconStyle(p.Href(), p.HotSpot(), children)
+}
+
+// Valid returns if p is a palette icon.
+func (p PaletteIcon) Valid() bool {
+	return 2 <= p.Palette && p.Palette <= 5 && 0 <= p.Index && p.Index < 64
+}
+
+// HotSpot returns the hot spot of p, the tip of the pin.
+func (p PushpinIcon) HotSpot() kml.Vec2 {
+	return pushpinHotSpot
+}
+
+// Href returns the href of p.
+func (p PushpinIcon) Href() string {
+	return PushpinHref(string(p))
+}
+
+// IconStyle returns an IconStyle for p with the hot spot set and the given
+// children.
+func (p PushpinIcon) IconStyle(children ...kml.Element) *kml.IconStyleElement {
+	return iconStyle(p.Href(), p.HotSpot(), children)
+}
+
+// Valid returns if p is a pushpin icon.
+func (p PushpinIcon) Valid() bool {
+	_, ok := pushpinIcons[p]
+	return ok
+}
+
+// HotSpot returns the hot spot of s, the center of the icon.
+func (s ShapeIcon) HotSpot() kml.Vec2 {
+	return centerHotSpot
+}
+
+// Href returns the href of s.
+func (s ShapeIcon) Href() string {
+	return ShapeHref(string(s))
+}
+
+// IconStyle returns an IconStyle for s with the hot spot set and the given
+// children.
+func (s ShapeIcon) IconStyle(children ...kml.Element) *kml.IconStyleElement {
+	return iconStyle(s.Href(), s.HotSpot(), children)
+}
+
+// Valid returns if s is a shape icon.
+func (s ShapeIcon) Valid() bool {
+	_, ok := shapeIcons[s]
+	return ok
+}
+
+// iconStyle returns an IconStyle for the icon at href with hotSpot and
+// children.
+func iconStyle(href string, hotSpot kml.Vec2, children []kml.Element) *kml.IconStyleElement {
+	return kml.IconStyle(children...).Append(
+		kml.HotSpot(hotSpot),
+		kml.Icon(
+			kml.Href(href),
+		),
+	)
+}
@@ -0,0 +1,143 @@
+package icon_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/icon"
+)
+
+func TestCatalogValid(t *testing.T) {
+	assert.True(t, icon.PushpinYellow.Valid())
+	assert.False(t, icon.PushpinIcon("yellow").Valid())
+	assert.True(t, icon.PaddleGo.Valid())
+	assert.True(t, icon.PaddleCharacter('A').Valid())
+	assert.True(t, icon.PaddleCharacter('9').Valid())
+	assert.True(t, icon.PaddleNumber(10).Valid())
+	assert.True(t, icon.ColoredPaddle(icon.PaddleLightBlue, icon.PaddleStars).Valid())
+	assert.False(t, icon.ColoredPaddle("blue", icon.PaddleStars).Valid())
+	assert.Equal(t, icon.PaddleIcon(""), icon.PaddleCharacter('a'))
+	assert.Equal(t, icon.PaddleIcon(""), icon.PaddleNumber(11))
+	assert.False(t, icon.PaddleIcon("").Valid())
+	assert.False(t, icon.PaddleIcon("0").Valid())
+	assert.True(t, icon.ShapeInfoI.Valid())
+	assert.False(t, icon.ShapeIcon("information").Valid())
+	assert.True(t, icon.PaletteIcon{Palette: 4, Index: 63}.Valid())
+	assert.False(t, icon.PaletteIcon{Palette: 1, Index: 0}.Valid())
+	assert.False(t, icon.PaletteIcon{Palette: 2, Index: 64}.Valid())
+	assert.False(t, icon.Entry{}.Valid())
+	assert.False(t, icon.Entry{Pushpin: icon.PushpinRed, Shape: icon.ShapeStar}.Valid())
+}
+
+func TestCatalogIconStyle(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		iconStyle *kml.IconStyleElement
+		expected  string
+	}{
+		{
+			name:      "pushpin",
+			iconStyle: icon.PushpinRed.IconStyle(kml.Scale(1.2)),
+			expected: `<IconStyle>` +
+				`<scale>1.2</scale>` +
+				`<hotSpot x="20" y="2" xunits="pixels" yunits="pixels"></hotSpot>` +
+				`<Icon><href>https://maps.google.com/mapfiles/kml/pushpin/red-pushpin.png</href></Icon>` +
+				`</IconStyle>`,
+		},
+		{
+			name:      "paddle",
+			iconStyle: icon.ColoredPaddle(icon.PaddleRed, icon.PaddleCircle).IconStyle(),
+			expected: `<IconStyle>` +
+				`<hotSpot x="0.5" y="0" xunits="fraction" yunits="fraction"></hotSpot>` +
+				`<Icon><href>https://maps.google.com/mapfiles/kml/paddle/red-circle.png</href></Icon>` +
+				`</IconStyle>`,
+		},
+		{
+			name:      "shape",
+			iconStyle: icon.ShapeAirports.IconStyle(),
+			expected: `<IconStyle>` +
+				`<hotSpot x="0.5" y="0.5" xunits="fraction" yunits="fraction"></hotSpot>` +
+				`<Icon><href>http://maps.google.com/mapfiles/kml/shapes/airports.png</href></Icon>` +
+				`</IconStyle>`,
+		},
+		{
+			name:      "palette",
+			iconStyle: icon.PaletteIcon{Palette: 3, Index: 17}.IconStyle(),
+			expected: `<IconStyle>` +
+				`<hotSpot x="0.5" y="0.5" xunits="fraction" yunits="fraction"></hotSpot>` +
+				`<Icon><href>https://maps.google.com/mapfiles/kml/pal3/icon17.png</href></Icon>` +
+				`</IconStyle>`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := xml.Marshal(tc.iconStyle)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(actual))
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, tc := range []struct {
+		href         string
+		expected     icon.Entry
+		expectedHref string
+		expectedErr  bool
+	}{
+		{
+			href:     icon.DefaultHref(),
+			expected: icon.Entry{Pushpin: icon.PushpinYellow},
+		},
+		{
+			href:         "http://maps.google.com/mapfiles/kml/pushpin/grn-pushpin.png",
+			expected:     icon.Entry{Pushpin: icon.PushpinGreen},
+			expectedHref: "https://maps.google.com/mapfiles/kml/pushpin/grn-pushpin.png",
+		},
+		{
+			href:     icon.PaddleHref("wht-blank"),
+			expected: icon.Entry{Paddle: icon.ColoredPaddle(icon.PaddleWhite, icon.PaddleBlank)},
+		},
+		{
+			href:     icon.ShapeHref("cross-hairs"),
+			expected: icon.Entry{Shape: icon.ShapeCrossHairs},
+		},
+		{
+			href:     icon.NoneHref(),
+			expected: icon.Entry{Palette: icon.PaletteIcon{Palette: 2, Index: 15}},
+		},
+		{
+			href:        icon.PushpinHref("yellow"),
+			expectedErr: true,
+		},
+		{
+			href:        "https://maps.google.com/mapfiles/kml/pal9/icon1.png",
+			expectedErr: true,
+		},
+		{
+			href:        "https://example.com/mapfiles/kml/shapes/airports.png",
+			expectedErr: true,
+		},
+		{
+			href:        "files/airports.png",
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.href, func(t *testing.T) {
+			actual, ok := icon.Lookup(tc.href)
+			if tc.expectedErr {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, actual)
+			expectedHref := tc.expectedHref
+			if expectedHref == "" {
+				expectedHref = tc.href
+			}
+			assert.Equal(t, expectedHref, actual.Href())
+		})
+	}
+}
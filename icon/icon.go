@@ -1,4 +1,5 @@
-// Package icon provides helper functions for standard Google Earth icons.
+// Package icon provides helper functions for standard Google Earth icons and
+// draws marker icons locally for offline use.
 // See http://kml4earth.appspot.com/icons.html.
 package icon
 
@@ -42,8 +43,8 @@ func NumberHref(n int) string {
 	return ""
 }
 
-// PaddleHref returns the href of the paddle icon with id. See
-// http://kml4earth.appspot.com/icons.html#paddle.
+// PaddleHref returns the href of the paddle icon with id. See PaddleIcon for a
+// typed alternative. See http://kml4earth.appspot.com/icons.html#paddle.
 func PaddleHref(id string) string {
 	return "https://maps.google.com/mapfiles/kml/paddle/" + id + ".png"
 }
@@ -51,12 +52,7 @@ func PaddleHref(id string) string {
 // PaddleIconStyle returns an IconStyle for the paddle icon with id and the
 // hotspot set. See http://kml4earth.appspot.com/icons.html#paddle.
 func PaddleIconStyle(id string) *kml.IconStyleElement {
-	return kml.IconStyle(
-		kml.HotSpot(kml.Vec2{X: 0.5, Y: 0, XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction}),
-		kml.Icon(
-			kml.Href(PaddleHref(id)),
-		),
-	)
+	return PaddleIcon(id).IconStyle()
 }
 
 // PaletteHref returns the href of icon in pal.
@@ -65,8 +61,8 @@ func PaletteHref(pal, icon int) string {
 }
 
 // PushpinHref returns the href of pushpin of color. Valid colors are blue,
-// green, ltblu, pink, purple, red, wht, and ylw. See
-// http://kml4earth.appspot.com/icons.html#pushpin.
+// grn, ltblu, pink, purple, red, wht, and ylw. See PushpinIcon for a typed
+// alternative. See http://kml4earth.appspot.com/icons.html#pushpin.
 func PushpinHref(color string) string {
 	return "https://maps.google.com/mapfiles/kml/pushpin/" + color + "-pushpin.png"
 }
@@ -0,0 +1,295 @@
+package icon
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"maps"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/internal/bitmapfont"
+)
+
+// DefaultMarkerSize is the size of markers if Marker.Size is zero.
+const DefaultMarkerSize = 64
+
+// DefaultMarkerColor is the color of markers if Marker.Color is nil.
+const DefaultMarkerColor = kml.ABGR(0xff00ffff)
+
+// Rendering parameters. Shapes are defined in a unit square with y down.
+const (
+	markerSamples   = 4    // Subpixel samples per pixel in each direction.
+	markerOutline   = 0.04 // Width of outlines.
+	markerTextRatio = 0.7  // Maximum width of text as a fraction of the head.
+)
+
+var (
+	markerOutlineColor = color.NRGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xff}
+	markerNeedleColor  = color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+)
+
+// A MarkerShape is the shape of a marker.
+type MarkerShape int
+
+// Marker shapes.
+const (
+	MarkerPushpin MarkerShape = iota
+	MarkerPaddle
+	MarkerCircle
+)
+
+var markerShapeNames = map[MarkerShape]string{
+	MarkerPushpin: "pushpin",
+	MarkerPaddle:  "paddle",
+	MarkerCircle:  "circle",
+}
+
+// A Marker is a marker icon with optional text drawn locally, for use in KMZ
+// files that must work offline. Text should be short, such as a number or a
+// letter, and is drawn with a built-in bitmap font that covers printable
+// ASCII characters.
+type Marker struct {
+	Shape     MarkerShape
+	Text      string
+	Color     color.Color // Fill color, or nil for DefaultMarkerColor.
+	TextColor color.Color // Text color, or nil for black or white, whichever contrasts more with Color.
+	Size      int         // Width and height in pixels. Zero means DefaultMarkerSize.
+}
+
+// A MarkerSet renders Markers and collects their images for a KMZ file.
+type MarkerSet struct {
+	dir   string
+	files map[string][]byte
+}
+
+// A markerGeometry describes where a shape is drawn.
+type markerGeometry struct {
+	head    [3]float64 // Center x, center y, and radius of the head.
+	tail    [3][2]float64
+	needle  [2][2]float64
+	hotSpot kml.Vec2
+}
+
+var markerGeometries = map[MarkerShape]markerGeometry{
+	MarkerPushpin: {
+		head:    [3]float64{0.62, 0.32, 0.28},
+		needle:  [2][2]float64{{0.3, 0.97}, {0.55, 0.45}},
+		hotSpot: kml.Vec2{X: 0.3, Y: 0.03, XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction},
+	},
+	MarkerPaddle: {
+		head:    [3]float64{0.5, 0.38, 0.34},
+		tail:    [3][2]float64{{0.26, 0.62}, {0.74, 0.62}, {0.5, 0.98}},
+		hotSpot: kml.Vec2{X: 0.5, Y: 0, XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction},
+	},
+	MarkerCircle: {
+		head:    [3]float64{0.5, 0.5, 0.45},
+		hotSpot: kml.Vec2{X: 0.5, Y: 0.5, XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction},
+	},
+}
+
+// NewMarkerSet returns a new MarkerSet that stores images in dir.
+func NewMarkerSet(dir string) *MarkerSet {
+	return &MarkerSet{
+		dir:   dir,
+		files: make(map[string][]byte),
+	}
+}
+
+// Filename returns a filename for m that is unique for its shape, color,
+// size, and text.
+func (m Marker) Filename() string {
+	var builder strings.Builder
+	builder.WriteString(markerShapeNames[m.Shape])
+	builder.WriteByte('-')
+	builder.WriteString(m.color().String())
+	builder.WriteByte('-')
+	builder.WriteString(strconv.Itoa(m.size()))
+	if m.TextColor != nil {
+		builder.WriteByte('-')
+		builder.WriteString(kml.ABGRModel.Convert(m.TextColor).(kml.ABGR).String()) //nolint:forcetypeassert
+	}
+	if m.Text != "" {
+		builder.WriteByte('-')
+		for _, b := range []byte(m.Text) {
+			if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' {
+				builder.WriteByte(b)
+			} else {
+				fmt.Fprintf(&builder, "_%02x", b)
+			}
+		}
+	}
+	builder.WriteString(".png")
+	return builder.String()
+}
+
+// HotSpot returns the hot spot of m: the tip of a pushpin's needle, the tip
+// of a paddle, or the center of a circle.
+func (m Marker) HotSpot() kml.Vec2 {
+	return markerGeometries[m.Shape].hotSpot
+}
+
+// IconStyle returns an IconStyle for m with the image at href, the hotspot
+// set, and the given children.
+func (m Marker) IconStyle(href string, children ...kml.Element) *kml.IconStyleElement {
+	return kml.IconStyle(children...).Append(
+		kml.HotSpot(m.HotSpot()),
+		kml.Icon(
+			kml.Href(href),
+		),
+	)
+}
+
+// Image returns the image of m.
+func (m Marker) Image() *image.NRGBA {
+	size := m.size()
+	geometry := markerGeometries[m.Shape]
+	fill := m.color().NRGBA()
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for py := range size {
+		for px := range size {
+			var r, g, b, a uint32
+			for sy := range markerSamples {
+				for sx := range markerSamples {
+					x := (float64(px) + (float64(sx)+0.5)/markerSamples) / float64(size)
+					y := (float64(py) + (float64(sy)+0.5)/markerSamples) / float64(size)
+					var c color.NRGBA
+					switch {
+					case geometry.contains(x, y, -markerOutline):
+						c = fill
+					case geometry.contains(x, y, 0):
+						c = markerOutlineColor
+					case geometry.needleContains(x, y):
+						c = markerNeedleColor
+					}
+					sr, sg, sb, sa := c.RGBA()
+					r, g, b, a = r+sr, g+sg, b+sb, a+sa
+				}
+			}
+			const n = markerSamples * markerSamples
+			img.Set(px, py, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}) //nolint:gosec
+		}
+	}
+	m.drawText(img, geometry)
+	return img
+}
+
+// PNG returns the image of m encoded as a PNG.
+func (m Marker) PNG() ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, m.Image()); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// color returns the fill color of m.
+func (m Marker) color() kml.ABGR {
+	if m.Color == nil {
+		return DefaultMarkerColor
+	}
+	return kml.ABGRModel.Convert(m.Color).(kml.ABGR) //nolint:forcetypeassert
+}
+
+// drawText draws the text of m centered on the head of geometry, at the
+// largest integer scale that fits.
+func (m Marker) drawText(img draw.Image, geometry markerGeometry) {
+	width := bitmapfont.TextWidth(m.Text)
+	if width == 0 {
+		return
+	}
+	size := float64(m.size())
+	diameter := 2 * geometry.head[2] * size
+	scale := max(1, int(min(markerTextRatio*diameter/float64(width), diameter/2/bitmapfont.Height)))
+	x := int(math.Round(geometry.head[0]*size)) - width*scale/2
+	y := int(math.Round(geometry.head[1]*size)) - bitmapfont.Height*scale/2
+	bitmapfont.Draw(img, image.Pt(x, y), m.Text, m.textColor(), scale)
+}
+
+// size returns the size of m.
+func (m Marker) size() int {
+	if m.Size <= 0 {
+		return DefaultMarkerSize
+	}
+	return m.Size
+}
+
+// textColor returns the text color of m.
+func (m Marker) textColor() color.NRGBA {
+	if m.TextColor != nil {
+		return kml.ABGRModel.Convert(m.TextColor).(kml.ABGR).NRGBA() //nolint:forcetypeassert
+	}
+	fill := m.color().NRGBA()
+	if 299*int(fill.R)+587*int(fill.G)+114*int(fill.B) > 128*1000 {
+		return color.NRGBA{A: 0xff}
+	}
+	return color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+}
+
+// Files returns the images of the markers in s, keyed by their paths, ready
+// to be added to the files passed to kml.WriteKMZ.
+func (s *MarkerSet) Files() map[string]any {
+	files := make(map[string]any, len(s.files))
+	for name, data := range s.files {
+		files[name] = data
+	}
+	return files
+}
+
+// IconStyle renders m, if it has not already been rendered, and returns an
+// IconStyle that refers to its image in s, with the hotspot set and the given
+// children.
+func (s *MarkerSet) IconStyle(m Marker, children ...kml.Element) (*kml.IconStyleElement, error) {
+	href := path.Join(s.dir, m.Filename())
+	if _, ok := s.files[href]; !ok {
+		data, err := m.PNG()
+		if err != nil {
+			return nil, err
+		}
+		s.files[href] = data
+	}
+	return m.IconStyle(href, children...), nil
+}
+
+// Paths returns the paths of the images in s.
+func (s *MarkerSet) Paths() []string {
+	return slices.Sorted(maps.Keys(s.files))
+}
+
+// contains returns if (x, y) is inside g grown by d.
+func (g markerGeometry) contains(x, y, d float64) bool {
+	if math.Hypot(x-g.head[0], y-g.head[1]) <= g.head[2]+d {
+		return true
+	}
+	if g.tail == [3][2]float64{} {
+		return false
+	}
+	for i := range 3 {
+		p0, p1 := g.tail[i], g.tail[(i+1)%3]
+		// Signed distance from the edge, positive inside for a clockwise
+		// triangle in y-down coordinates.
+		ex, ey := p1[0]-p0[0], p1[1]-p0[1]
+		if (ex*(y-p0[1])-ey*(x-p0[0]))/math.Hypot(ex, ey) < -d {
+			return false
+		}
+	}
+	return true
+}
+
+// needleContains returns if (x, y) is on the needle of g.
+func (g markerGeometry) needleContains(x, y float64) bool {
+	if g.needle == [2][2]float64{} {
+		return false
+	}
+	p0, p1 := g.needle[0], g.needle[1]
+	dx, dy := p1[0]-p0[0], p1[1]-p0[1]
+	t := min(max(((x-p0[0])*dx+(y-p0[1])*dy)/(dx*dx+dy*dy), 0), 1)
+	return math.Hypot(x-p0[0]-t*dx, y-p0[1]-t*dy) <= markerOutline
+}
@@ -0,0 +1,127 @@
+package icon_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/icon"
+)
+
+func TestMarkerFilename(t *testing.T) {
+	for _, tc := range []struct {
+		marker   icon.Marker
+		expected string
+	}{
+		{
+			marker:   icon.Marker{},
+			expected: "pushpin-ff00ffff-64.png",
+		},
+		{
+			marker:   icon.Marker{Shape: icon.MarkerPaddle, Text: "A", Color: kml.ABGR(0xff0000ff)},
+			expected: "paddle-ff0000ff-64-A.png",
+		},
+		{
+			marker:   icon.Marker{Shape: icon.MarkerCircle, Text: "1/2 ", Size: 32, TextColor: color.White},
+			expected: "circle-ff00ffff-32-ffffffff-1_2f2_20.png",
+		},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.marker.Filename())
+		})
+	}
+}
+
+func TestMarkerImage(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		marker   icon.Marker
+		hotSpot  image.Point
+		inside   image.Point
+		expected color.NRGBA
+	}{
+		{
+			name:     "pushpin",
+			marker:   icon.Marker{Shape: icon.MarkerPushpin, Color: kml.ABGR(0xff0000ff)},
+			hotSpot:  image.Pt(19, 62),
+			inside:   image.Pt(50, 12),
+			expected: color.NRGBA{R: 0xff, A: 0xff},
+		},
+		{
+			name:     "paddle",
+			marker:   icon.Marker{Shape: icon.MarkerPaddle, Color: kml.ABGR(0xff00ff00)},
+			hotSpot:  image.Pt(32, 61),
+			inside:   image.Pt(32, 45),
+			expected: color.NRGBA{G: 0xff, A: 0xff},
+		},
+		{
+			name:     "circle",
+			marker:   icon.Marker{Shape: icon.MarkerCircle, Color: kml.ABGR(0x80ff0000), Size: 32},
+			hotSpot:  image.Pt(16, 16),
+			inside:   image.Pt(16, 8),
+			expected: color.NRGBA{B: 0xff, A: 0x80},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.marker.PNG()
+			assert.NoError(t, err)
+			img, err := png.Decode(bytes.NewReader(data))
+			assert.NoError(t, err)
+			size := img.Bounds().Dx()
+			assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds())
+			assert.Equal(t, color.Color(color.NRGBA{}), color.NRGBAModel.Convert(img.At(0, 0)))
+			assert.Equal(t, color.Color(tc.expected), color.NRGBAModel.Convert(img.At(tc.inside.X, tc.inside.Y)))
+			_, _, _, alpha := img.At(tc.hotSpot.X, tc.hotSpot.Y).RGBA()
+			assert.NotEqual(t, 0, alpha)
+		})
+	}
+}
+
+func TestMarkerText(t *testing.T) {
+	blank := icon.Marker{Shape: icon.MarkerCircle, Color: color.White}.Image()
+	img := icon.Marker{Shape: icon.MarkerCircle, Color: color.White, Text: "8"}.Image()
+	differences := 0
+	for y := range 64 {
+		for x := range 64 {
+			if img.NRGBAAt(x, y) != blank.NRGBAAt(x, y) {
+				assert.Equal(t, color.NRGBA{A: 0xff}, img.NRGBAAt(x, y))
+				differences++
+			}
+		}
+	}
+	assert.NotEqual(t, 0, differences)
+}
+
+func TestMarkerSet(t *testing.T) {
+	markerSet := icon.NewMarkerSet("files")
+	marker := icon.Marker{Shape: icon.MarkerPaddle, Text: "1"}
+	iconStyle, err := markerSet.IconStyle(marker, kml.Scale(1.5))
+	assert.NoError(t, err)
+	actual, err := xml.Marshal(iconStyle)
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<IconStyle>`+
+		`<scale>1.5</scale>`+
+		`<hotSpot x="0.5" y="0" xunits="fraction" yunits="fraction"></hotSpot>`+
+		`<Icon><href>files/paddle-ff00ffff-64-1.png</href></Icon>`+
+		`</IconStyle>`,
+		string(actual))
+
+	_, err = markerSet.IconStyle(marker)
+	assert.NoError(t, err)
+	_, err = markerSet.IconStyle(icon.Marker{Shape: icon.MarkerCircle, Text: "2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"files/circle-ff00ffff-64-2.png", "files/paddle-ff00ffff-64-1.png"}, markerSet.Paths())
+
+	files := markerSet.Files()
+	assert.Equal(t, 2, len(files))
+	files["doc.kml"] = kml.KML(kml.Placemark(kml.Style(iconStyle)))
+	var buffer bytes.Buffer
+	assert.NoError(t, kml.WriteKMZ(&buffer, files))
+}
@@ -0,0 +1,76 @@
+package icon
+
+import (
+	"math"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+// numTrackIcons is the number of directional track icons. Track icon i points
+// in the direction 360*i/numTrackIcons degrees clockwise from north.
+const numTrackIcons = 16
+
+// DirectionMarkers returns a Placemark every interval meters along cs, not
+// including the start, each with a Point and a Style with the track icon
+// pointing in the direction of cs at that point. Distances and bearings are
+// calculated with g, for example sphere.WGS84. Altitudes are interpolated
+// linearly. children are added to each Placemark.
+func DirectionMarkers(g sphere.Geodesic, cs []kml.Coordinate, interval float64, children ...kml.Element) []*kml.PlacemarkElement {
+	if !(interval > 0) {
+		return nil
+	}
+	var placemarks []*kml.PlacemarkElement
+	start, next := 0.0, interval
+	for i := 1; i < len(cs); i++ {
+		c1, c2 := cs[i-1], cs[i]
+		length := g.Distance(c1, c2)
+		bearing := g.InitialBearingTo(c1, c2)
+		for next < start+length {
+			f := (next - start) / length
+			c := g.Offset(c1, next-start, bearing)
+			c.Alt = c1.Alt + f*(c2.Alt-c1.Alt)
+			placemarks = append(placemarks, kml.Placemark(children...).Append(
+				kml.Style(TrackIconStyle(g.InitialBearingTo(c, c2))),
+				kml.Point(kml.Coordinates(c)),
+			))
+			next += interval
+		}
+		start += length
+	}
+	return placemarks
+}
+
+// HeadingIconStyle returns an IconStyle for the icon at href rotated to
+// heading, in degrees clockwise from north, with the given children. It is an
+// alternative to TrackIconStyle for arbitrary headings and icons. The icon
+// should point north when not rotated.
+func HeadingIconStyle(href string, heading float64, children ...kml.Element) *kml.IconStyleElement {
+	return kml.IconStyle(children...).Append(
+		kml.Heading(heading),
+		kml.Icon(
+			kml.Href(href),
+		),
+	)
+}
+
+// TrackHeadingHref returns the href of the track icon that points closest to
+// heading, in degrees clockwise from north, or TrackNoneHref if heading is
+// NaN or infinite. See http://kml4earth.appspot.com/icons.html#kml-icons.
+func TrackHeadingHref(heading float64) string {
+	if math.IsNaN(heading) || math.IsInf(heading, 0) {
+		return TrackNoneHref()
+	}
+	i := int(math.Round(heading*numTrackIcons/360)) % numTrackIcons
+	if i < 0 {
+		i += numTrackIcons
+	}
+	return TrackHref(i)
+}
+
+// TrackIconStyle returns an IconStyle for the track icon that points closest
+// to heading, as returned by TrackHeadingHref, with the hot spot at its center
+// and the given children.
+func TrackIconStyle(heading float64, children ...kml.Element) *kml.IconStyleElement {
+	return iconStyle(TrackHeadingHref(heading), centerHotSpot, children)
+}
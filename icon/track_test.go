@@ -0,0 +1,104 @@
+package icon_test
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/icon"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+func TestTrackHeadingHref(t *testing.T) {
+	for _, tc := range []struct {
+		heading  float64
+		expected string
+	}{
+		{heading: 0, expected: icon.TrackHref(0)},
+		{heading: 11, expected: icon.TrackHref(0)},
+		{heading: 12, expected: icon.TrackHref(1)},
+		{heading: 90, expected: icon.TrackHref(4)},
+		{heading: 180, expected: icon.TrackHref(8)},
+		{heading: 350, expected: icon.TrackHref(0)},
+		{heading: 360, expected: icon.TrackHref(0)},
+		{heading: -90, expected: icon.TrackHref(12)},
+		{heading: 450, expected: icon.TrackHref(4)},
+		{heading: math.NaN(), expected: icon.TrackNoneHref()},
+		{heading: math.Inf(1), expected: icon.TrackNoneHref()},
+	} {
+		t.Run(strconv.FormatFloat(tc.heading, 'f', -1, 64), func(t *testing.T) {
+			assert.Equal(t, tc.expected, icon.TrackHeadingHref(tc.heading))
+		})
+	}
+}
+
+func TestTrackIconStyle(t *testing.T) {
+	actual, err := xml.Marshal(icon.TrackIconStyle(45, kml.Scale(0.5)))
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<IconStyle>`+
+		`<scale>0.5</scale>`+
+		`<hotSpot x="0.5" y="0.5" xunits="fraction" yunits="fraction"></hotSpot>`+
+		`<Icon><href>https://earth.google.com/images/kml-icons/track-directional/track-2.png</href></Icon>`+
+		`</IconStyle>`,
+		string(actual))
+
+	actual, err = xml.Marshal(icon.HeadingIconStyle("arrow.png", 123.5))
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<IconStyle>`+
+		`<heading>123.5</heading>`+
+		`<Icon><href>arrow.png</href></Icon>`+
+		`</IconStyle>`,
+		string(actual))
+}
+
+func TestDirectionMarkers(t *testing.T) {
+	// A track 1km east along the equator and then 1.5km north.
+	s := sphere.WGS84
+	c0 := kml.Coordinate{Lon: 0, Lat: 0, Alt: 100}
+	c1 := s.Offset(c0, 1000, 90)
+	c1.Alt = 200
+	c2 := s.Offset(c1, 1500, 0)
+	c2.Alt = 200
+	placemarks := icon.DirectionMarkers(s, []kml.Coordinate{c0, c1, c2}, 400, kml.Name("marker"))
+	assert.Equal(t, 6, len(placemarks))
+
+	for i, placemark := range placemarks {
+		assert.Equal(t, 3, len(placemark.Children))
+		assert.Equal(t, kml.Element(kml.Name("marker")), placemark.Children[0])
+		c := placemark.Children[2].(*kml.PointElement).Children[0].(kml.CoordinatesElement)[0] //nolint:forcetypeassert
+		distance := 400 * float64(i+1)
+		expectedHref := icon.TrackHref(4)
+		if distance < 1000 {
+			assertInDelta(t, distance, s.Distance(c0, c), 1e-6)
+			assertInDelta(t, 100+distance/10, c.Alt, 1e-6)
+		} else {
+			expectedHref = icon.TrackHref(0)
+			assertInDelta(t, distance-1000, s.Distance(c1, c), 1e-6)
+			assertInDelta(t, 200, c.Alt, 1e-6)
+		}
+		actual, err := xml.Marshal(placemark.Children[1])
+		assert.NoError(t, err)
+		assert.Equal(t, `<Style><IconStyle>`+
+			`<hotSpot x="0.5" y="0.5" xunits="fraction" yunits="fraction"></hotSpot>`+
+			`<Icon><href>`+expectedHref+`</href></Icon>`+
+			`</IconStyle></Style>`,
+			string(actual))
+	}
+
+	assert.Zero(t, icon.DirectionMarkers(s, []kml.Coordinate{c0, c1}, 0))
+	assert.Zero(t, icon.DirectionMarkers(s, []kml.Coordinate{c0}, 100))
+}
+
+func assertInDelta(tb testing.TB, expected, actual, delta float64) {
+	tb.Helper()
+	if math.Abs(expected-actual) <= delta {
+		return
+	}
+	tb.Fatalf("Expected %f to be within %f of %f", actual, delta, expected)
+}
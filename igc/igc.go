@@ -0,0 +1,279 @@
+// Package igc parses IGC flight logs and converts them to KML.
+//
+// See https://www.fai.org/sites/default/files/igc_fr_specification_2020-11-25_with_al6.pdf.
+package igc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBackwardsStep is the largest backwards step in fix times that is not
+// taken to be a day rollover.
+const maxBackwardsStep = 12 * time.Hour
+
+var errMissingDate = errors.New("igc: B record before date")
+
+// An Extension is an extension to B records declared in an I record.
+type Extension struct {
+	Code  string // Three letter code, for example FXA or ENL.
+	Start int    // Zero-based offset of the first byte in the B record.
+	End   int    // Zero-based offset of the byte after the last byte in the B record.
+}
+
+// A Fix is a B record.
+type Fix struct {
+	Time        time.Time
+	Lat         float64
+	Lon         float64
+	Valid       bool // True for 3D fixes, false for 2D fixes or no GNSS data.
+	PressureAlt int  // Pressure altitude in meters.
+	GNSSAlt     int  // GNSS altitude in meters.
+	Extensions  []int
+}
+
+// A Turnpoint is a point in a task declaration.
+type Turnpoint struct {
+	Lat  float64
+	Lon  float64
+	Name string
+}
+
+// A Task is a task declaration from C records.
+type Task struct {
+	DeclarationTime time.Time
+	Description     string
+	Turnpoints      []Turnpoint // Including takeoff, start, finish, and landing.
+}
+
+// A Flight is a parsed IGC file.
+type Flight struct {
+	Headers    map[string]string // H records keyed by three letter code, for example PLT.
+	Date       time.Time
+	Extensions []Extension // Extensions in Fixes, in order.
+	Fixes      []Fix
+	Task       *Task
+}
+
+// Parse parses an IGC file from r. Records other than B, C, H, and I records
+// are ignored. Fix times after midnight UTC are advanced to the next day. A
+// fix is only taken to be on the next day if its time is more than 12 hours
+// before the previous fix's, so small backwards steps from logger jitter or out
+// of order records do not move the rest of the flight.
+func Parse(r io.Reader) (*Flight, error) {
+	flight := &Flight{
+		Headers: make(map[string]string),
+	}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	var prevTime time.Time
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if line == "" {
+			continue
+		}
+		var err error
+		switch line[0] {
+		case 'B':
+			var fix Fix
+			if fix, err = flight.parseB(line); err == nil {
+				for !prevTime.IsZero() && prevTime.Sub(fix.Time) > maxBackwardsStep {
+					fix.Time = fix.Time.AddDate(0, 0, 1)
+				}
+				prevTime = fix.Time
+				flight.Fixes = append(flight.Fixes, fix)
+			}
+		case 'C':
+			err = flight.parseC(line)
+		case 'H':
+			err = flight.parseH(line)
+		case 'I':
+			flight.Extensions, err = parseI(line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("igc: line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return flight, nil
+}
+
+// parseB parses a B record.
+func (f *Flight) parseB(line string) (Fix, error) {
+	if len(line) < 35 {
+		return Fix{}, errors.New("B record too short")
+	}
+	if f.Date.IsZero() {
+		return Fix{}, errMissingDate
+	}
+	hour, minute, second, err := parseHHMMSS(line[1:7])
+	if err != nil {
+		return Fix{}, err
+	}
+	lat, lon, err := parseLatLon(line[7:24])
+	if err != nil {
+		return Fix{}, err
+	}
+	pressureAlt, err := strconv.Atoi(line[25:30])
+	if err != nil {
+		return Fix{}, fmt.Errorf("invalid pressure altitude: %q", line[25:30])
+	}
+	gnssAlt, err := strconv.Atoi(line[30:35])
+	if err != nil {
+		return Fix{}, fmt.Errorf("invalid GNSS altitude: %q", line[30:35])
+	}
+	fix := Fix{
+		Time:        f.Date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second),
+		Lat:         lat,
+		Lon:         lon,
+		Valid:       line[24] == 'A',
+		PressureAlt: pressureAlt,
+		GNSSAlt:     gnssAlt,
+	}
+	if len(f.Extensions) > 0 {
+		fix.Extensions = make([]int, len(f.Extensions))
+		for i, extension := range f.Extensions {
+			if extension.End > len(line) {
+				continue
+			}
+			value, err := strconv.Atoi(strings.TrimSpace(line[extension.Start:extension.End]))
+			if err != nil {
+				return Fix{}, fmt.Errorf("invalid %s: %q", extension.Code, line[extension.Start:extension.End])
+			}
+			fix.Extensions[i] = value
+		}
+	}
+	return fix, nil
+}
+
+// parseC parses a C record.
+func (f *Flight) parseC(line string) error {
+	if f.Task == nil {
+		if len(line) < 25 {
+			return errors.New("C record too short")
+		}
+		declarationTime, err := time.Parse("020106150405", line[1:13])
+		if err != nil {
+			return fmt.Errorf("invalid declaration time: %q", line[1:13])
+		}
+		f.Task = &Task{
+			DeclarationTime: declarationTime,
+			Description:     strings.TrimSpace(line[25:]),
+		}
+		return nil
+	}
+	if len(line) < 18 {
+		return errors.New("C record too short")
+	}
+	lat, lon, err := parseLatLon(line[1:18])
+	if err != nil {
+		return err
+	}
+	if lat == 0 && lon == 0 {
+		return nil
+	}
+	f.Task.Turnpoints = append(f.Task.Turnpoints, Turnpoint{
+		Lat:  lat,
+		Lon:  lon,
+		Name: strings.TrimSpace(line[18:]),
+	})
+	return nil
+}
+
+// parseH parses an H record.
+func (f *Flight) parseH(line string) error {
+	if len(line) < 5 {
+		return errors.New("H record too short")
+	}
+	code := line[2:5]
+	value := line[5:]
+	if _, after, ok := strings.Cut(value, ":"); ok {
+		value = after
+	}
+	value = strings.TrimSpace(value)
+	f.Headers[code] = value
+	if code == "DTE" {
+		if len(value) < 6 {
+			return fmt.Errorf("invalid date: %q", value)
+		}
+		date, err := time.Parse("020106", value[:6])
+		if err != nil {
+			return fmt.Errorf("invalid date: %q", value)
+		}
+		f.Date = date
+	}
+	return nil
+}
+
+// parseI parses an I record.
+func parseI(line string) ([]Extension, error) {
+	if len(line) < 3 {
+		return nil, errors.New("I record too short")
+	}
+	n, err := strconv.Atoi(line[1:3])
+	if err != nil || len(line) < 3+7*n {
+		return nil, fmt.Errorf("invalid I record: %q", line)
+	}
+	extensions := make([]Extension, 0, n)
+	for i := range n {
+		s := line[3+7*i : 10+7*i]
+		start, err1 := strconv.Atoi(s[0:2])
+		end, err2 := strconv.Atoi(s[2:4])
+		if err1 != nil || err2 != nil || start < 36 || end < start {
+			return nil, fmt.Errorf("invalid extension: %q", s)
+		}
+		extensions = append(extensions, Extension{
+			Code:  s[4:7],
+			Start: start - 1,
+			End:   end,
+		})
+	}
+	return extensions, nil
+}
+
+// parseHHMMSS parses a time of day.
+func parseHHMMSS(s string) (int, int, int, error) {
+	hour, err1 := strconv.Atoi(s[0:2])
+	minute, err2 := strconv.Atoi(s[2:4])
+	second, err3 := strconv.Atoi(s[4:6])
+	if err := errors.Join(err1, err2, err3); err != nil || hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, fmt.Errorf("invalid time: %q", s)
+	}
+	return hour, minute, second, nil
+}
+
+// parseLatLon parses a latitude and longitude in the form DDMMmmmNDDDMMmmmE.
+func parseLatLon(s string) (float64, float64, error) {
+	latDeg, err1 := strconv.Atoi(s[0:2])
+	latMMin, err2 := strconv.Atoi(s[2:7])
+	lonDeg, err3 := strconv.Atoi(s[8:11])
+	lonMMin, err4 := strconv.Atoi(s[11:16])
+	if err := errors.Join(err1, err2, err3, err4); err != nil || latDeg > 90 || lonDeg > 180 {
+		return 0, 0, fmt.Errorf("invalid position: %q", s)
+	}
+	lat := float64(latDeg) + float64(latMMin)/60000
+	lon := float64(lonDeg) + float64(lonMMin)/60000
+	switch s[7] {
+	case 'N':
+	case 'S':
+		lat = -lat
+	default:
+		return 0, 0, fmt.Errorf("invalid position: %q", s)
+	}
+	switch s[16] {
+	case 'E':
+	case 'W':
+		lon = -lon
+	default:
+		return 0, 0, fmt.Errorf("invalid position: %q", s)
+	}
+	return lat, lon, nil
+}
@@ -0,0 +1,172 @@
+package igc_test
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3/igc"
+)
+
+const testIGC = "" +
+	"AXXX001 Test logger\r\n" +
+	"HFDTEDATE:150724,01\r\n" +
+	"HFPLTPILOTINCHARGE: Jane Doe\r\n" +
+	"HFGTYGLIDERTYPE:Ozone Zeno 2\r\n" +
+	"I023638FXA3940SIU\r\n" +
+	"C150724083000150724000102Triangle\r\n" +
+	"C0000000N00000000ETAKEOFF\r\n" +
+	"C4600000N00800000ESTART\r\n" +
+	"C4606000N00806000ETP1\r\n" +
+	"C4600000N00800000EFINISH\r\n" +
+	"C0000000N00000000ELANDING\r\n" +
+	"B2359504600000N00800000EA010000110002312\r\n" +
+	"B2359554601000N00800000EA010100111002312\r\n" +
+	"B0000004602000N00800000EV010050110502413\r\n" +
+	"LXXXComment\r\n" +
+	"GABCDEF\r\n"
+
+func TestParse(t *testing.T) {
+	flight, err := igc.Parse(strings.NewReader(testIGC))
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", flight.Headers["PLT"])
+	assert.Equal(t, "Ozone Zeno 2", flight.Headers["GTY"])
+	assert.Equal(t, time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC), flight.Date)
+	assert.Equal(t, []igc.Extension{
+		{Code: "FXA", Start: 35, End: 38},
+		{Code: "SIU", Start: 38, End: 40},
+	}, flight.Extensions)
+
+	assert.Equal(t, 3, len(flight.Fixes))
+	assert.Equal(t, igc.Fix{
+		Time:        time.Date(2024, 7, 15, 23, 59, 50, 0, time.UTC),
+		Lat:         46,
+		Lon:         8,
+		Valid:       true,
+		PressureAlt: 1000,
+		GNSSAlt:     1100,
+		Extensions:  []int{23, 12},
+	}, flight.Fixes[0])
+	assert.Equal(t, time.Date(2024, 7, 16, 0, 0, 0, 0, time.UTC), flight.Fixes[2].Time)
+	assert.False(t, flight.Fixes[2].Valid)
+	assert.True(t, math.Abs(flight.Fixes[1].Lat-(46+1/60.0)) < 1e-9)
+
+	assert.Equal(t, &igc.Task{
+		DeclarationTime: time.Date(2024, 7, 15, 8, 30, 0, 0, time.UTC),
+		Description:     "Triangle",
+		Turnpoints: []igc.Turnpoint{
+			{Lat: 46, Lon: 8, Name: "START"},
+			{Lat: 46.1, Lon: 8.1, Name: "TP1"},
+			{Lat: 46, Lon: 8, Name: "FINISH"},
+		},
+	}, flight.Task)
+}
+
+func TestParseBackwardsStep(t *testing.T) {
+	flight, err := igc.Parse(strings.NewReader("" +
+		"HFDTE150724\r\n" +
+		"B1200004600000N00800000EA0100001100\r\n" +
+		"B1200054600000N00800000EA0100001100\r\n" +
+		"B1200034600000N00800000EA0100001100\r\n" +
+		"B1200104600000N00800000EA0100001100\r\n",
+	))
+	assert.NoError(t, err)
+	var times []time.Time
+	for _, fix := range flight.Fixes {
+		times = append(times, fix.Time)
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 15, 12, 0, 5, 0, time.UTC),
+		time.Date(2024, 7, 15, 12, 0, 3, 0, time.UTC),
+		time.Date(2024, 7, 15, 12, 0, 10, 0, time.UTC),
+	}, times)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		igc  string
+	}{
+		{
+			name: "missing_date",
+			igc:  "B2359504600000N00800000EA010000110002312\n",
+		},
+		{
+			name: "short_b_record",
+			igc:  "HFDTE150724\nB2359504600000N00800000EA01000\n",
+		},
+		{
+			name: "invalid_position",
+			igc:  "HFDTE150724\nB2359504600000X00800000EA010000110002312\n",
+		},
+		{
+			name: "invalid_time",
+			igc:  "HFDTE150724\nB2369504600000N00800000EA010000110002312\n",
+		},
+		{
+			name: "invalid_date",
+			igc:  "HFDTE153224\n",
+		},
+		{
+			name: "invalid_extension",
+			igc:  "HFDTE150724\nI013638FXA\nB2359504600000N00800000EA0100001100XYZ\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := igc.Parse(strings.NewReader(tc.igc))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDocument(t *testing.T) {
+	flight, err := igc.Parse(strings.NewReader(testIGC))
+	assert.NoError(t, err)
+	actual, err := xml.Marshal(flight.Document())
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		`<Document>`+
+		`<name>Jane Doe 2024-07-15</name>`+
+		`<Schema id="igc">`+
+		`<gx:SimpleArrayField name="pressureAltitude" type="int"><displayName>Pressure altitude (m)</displayName></gx:SimpleArrayField>`+
+		`<gx:SimpleArrayField name="vario" type="float"><displayName>Vario (m/s)</displayName></gx:SimpleArrayField>`+
+		`<gx:SimpleArrayField name="groundSpeed" type="float"><displayName>Ground speed (km/h)</displayName></gx:SimpleArrayField>`+
+		`<gx:SimpleArrayField name="FXA" type="int"><displayName>FXA</displayName></gx:SimpleArrayField>`+
+		`<gx:SimpleArrayField name="SIU" type="int"><displayName>SIU</displayName></gx:SimpleArrayField>`+
+		`</Schema>`+
+		`<Placemark>`+
+		`<name>Track</name>`+
+		`<gx:Track>`+
+		`<altitudeMode>absolute</altitudeMode>`+
+		`<when>2024-07-15T23:59:50Z</when>`+
+		`<when>2024-07-15T23:59:55Z</when>`+
+		`<when>2024-07-16T00:00:00Z</when>`+
+		`<gx:coord>8 46 1100</gx:coord>`+
+		`<gx:coord>8 46.016666666666666 1110</gx:coord>`+
+		`<gx:coord>8 46.03333333333333 1105</gx:coord>`+
+		`<ExtendedData>`+
+		`<SchemaData schemaUrl="#igc">`+
+		`<gx:SimpleArrayData name="pressureAltitude"><gx:value>1000</gx:value><gx:value>1010</gx:value><gx:value>1005</gx:value></gx:SimpleArrayData>`+
+		`<gx:SimpleArrayData name="vario"><gx:value>0.0</gx:value><gx:value>2.0</gx:value><gx:value>-1.0</gx:value></gx:SimpleArrayData>`+
+		`<gx:SimpleArrayData name="groundSpeed"><gx:value>0.0</gx:value><gx:value>1334.3</gx:value><gx:value>1334.3</gx:value></gx:SimpleArrayData>`+
+		`<gx:SimpleArrayData name="FXA"><gx:value>23</gx:value><gx:value>23</gx:value><gx:value>24</gx:value></gx:SimpleArrayData>`+
+		`<gx:SimpleArrayData name="SIU"><gx:value>12</gx:value><gx:value>12</gx:value><gx:value>13</gx:value></gx:SimpleArrayData>`+
+		`</SchemaData>`+
+		`</ExtendedData>`+
+		`</gx:Track>`+
+		`</Placemark>`+
+		`<Folder>`+
+		`<name>Task</name>`+
+		`<description>Triangle</description>`+
+		`<Placemark><name>START</name><Point><coordinates>8,46</coordinates></Point></Placemark>`+
+		`<Placemark><name>TP1</name><Point><coordinates>8.1,46.1</coordinates></Point></Placemark>`+
+		`<Placemark><name>FINISH</name><Point><coordinates>8,46</coordinates></Point></Placemark>`+
+		`</Folder>`+
+		`</Document>`,
+		string(actual))
+}
@@ -0,0 +1,153 @@
+package igc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+// SchemaID is the id of the Schema returned by Flight.Schema.
+const SchemaID = "igc"
+
+// Document returns a Document named after the pilot and date containing the
+// schema, a Placemark with the track, and a Folder with the task, if any.
+// children are added to the Document before the schema.
+func (f *Flight) Document(children ...kml.Element) *kml.DocumentElement {
+	document := kml.Document()
+	if name := f.name(); name != "" {
+		document.Append(kml.Name(name))
+	}
+	document.Append(children...)
+	document.Append(
+		f.Schema(),
+		kml.Placemark(
+			kml.Name("Track"),
+			f.Track(),
+		),
+	)
+	if f.Task != nil {
+		document.Append(f.TaskFolder())
+	}
+	return document
+}
+
+// Schema returns a Schema describing the arrays in the ExtendedData of the
+// track returned by Track.
+func (f *Flight) Schema() *kml.SchemaElement {
+	schema := kml.Schema(SchemaID,
+		kml.GxSimpleArrayField("pressureAltitude", "int", kml.DisplayName("Pressure altitude (m)")),
+		kml.GxSimpleArrayField("vario", "float", kml.DisplayName("Vario (m/s)")),
+		kml.GxSimpleArrayField("groundSpeed", "float", kml.DisplayName("Ground speed (km/h)")),
+	)
+	for _, extension := range f.Extensions {
+		schema.Append(kml.GxSimpleArrayField(extension.Code, "int", kml.DisplayName(extension.Code)))
+	}
+	return schema
+}
+
+// TaskFolder returns a Folder containing a Placemark for each turnpoint of the
+// task. It returns nil if there is no task.
+func (f *Flight) TaskFolder() *kml.FolderElement {
+	if f.Task == nil {
+		return nil
+	}
+	folder := kml.Folder(kml.Name("Task"))
+	if f.Task.Description != "" {
+		folder.Append(kml.Description(f.Task.Description))
+	}
+	for _, turnpoint := range f.Task.Turnpoints {
+		folder.Append(
+			kml.Placemark(
+				kml.Name(turnpoint.Name),
+				kml.Point(
+					kml.Coordinates(kml.Coordinate{Lon: turnpoint.Lon, Lat: turnpoint.Lat}),
+				),
+			),
+		)
+	}
+	return folder
+}
+
+// Track returns a gx:Track of the fixes with absolute altitudes and
+// ExtendedData containing the pressure altitude, vario, ground speed, and
+// extensions of each fix, as described by Schema. Altitudes are GNSS altitudes
+// if any fix has a GNSS altitude and pressure altitudes otherwise, and the
+// vario is calculated from the same altitudes. children are added to the
+// gx:Track.
+func (f *Flight) Track(children ...kml.Element) *kml.GxTrackElement {
+	useGNSSAlt := false
+	for _, fix := range f.Fixes {
+		if fix.GNSSAlt != 0 {
+			useGNSSAlt = true
+			break
+		}
+	}
+	alt := func(fix Fix) float64 {
+		if useGNSSAlt {
+			return float64(fix.GNSSAlt)
+		}
+		return float64(fix.PressureAlt)
+	}
+
+	n := len(f.Fixes)
+	whens := make([]kml.Element, 0, n)
+	coords := make([]kml.Element, 0, n)
+	pressureAltitudes := make([]kml.Element, 0, n)
+	varios := make([]kml.Element, 0, n)
+	groundSpeeds := make([]kml.Element, 0, n)
+	extensions := make([][]kml.Element, len(f.Extensions))
+	for i, fix := range f.Fixes {
+		coordinate := kml.Coordinate{Lon: fix.Lon, Lat: fix.Lat, Alt: alt(fix)}
+		vario, groundSpeed := 0.0, 0.0
+		if i > 0 {
+			prevFix := f.Fixes[i-1]
+			if dt := fix.Time.Sub(prevFix.Time).Seconds(); dt > 0 {
+				vario = (alt(fix) - alt(prevFix)) / dt
+				distance := sphere.FAI.HaversineDistance(kml.Coordinate{Lon: prevFix.Lon, Lat: prevFix.Lat}, coordinate)
+				groundSpeed = 3.6 * distance / dt
+			}
+		}
+		whens = append(whens, kml.When(fix.Time))
+		coords = append(coords, kml.GxCoord(coordinate))
+		pressureAltitudes = append(pressureAltitudes, kml.GxValue(strconv.Itoa(fix.PressureAlt)))
+		varios = append(varios, kml.GxValue(strconv.FormatFloat(vario, 'f', 1, 64)))
+		groundSpeeds = append(groundSpeeds, kml.GxValue(strconv.FormatFloat(groundSpeed, 'f', 1, 64)))
+		for j := range f.Extensions {
+			value := 0
+			if j < len(fix.Extensions) {
+				value = fix.Extensions[j]
+			}
+			extensions[j] = append(extensions[j], kml.GxValue(strconv.Itoa(value)))
+		}
+	}
+
+	schemaData := kml.SchemaData("#"+SchemaID,
+		kml.GxSimpleArrayData("pressureAltitude", pressureAltitudes...),
+		kml.GxSimpleArrayData("vario", varios...),
+		kml.GxSimpleArrayData("groundSpeed", groundSpeeds...),
+	)
+	for i, extension := range f.Extensions {
+		schemaData.Append(kml.GxSimpleArrayData(extension.Code, extensions[i]...))
+	}
+
+	track := kml.GxTrack(children...)
+	track.Append(kml.AltitudeMode(kml.AltitudeModeAbsolute))
+	track.Append(whens...)
+	track.Append(coords...)
+	track.Append(kml.ExtendedData(schemaData))
+	return track
+}
+
+// name returns a name for the flight from the pilot and date.
+func (f *Flight) name() string {
+	var parts []string
+	if pilot := f.Headers["PLT"]; pilot != "" {
+		parts = append(parts, pilot)
+	}
+	if !f.Date.IsZero() {
+		parts = append(parts, f.Date.Format("2006-01-02"))
+	}
+	return strings.Join(parts, " ")
+}
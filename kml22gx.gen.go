@@ -267,8 +267,13 @@ func GxOuterColor(value color.Color) *GxOuterColorElement {
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxOuterColorElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:outerColor"}}
-	red, green, blue, alpha := e.Value.RGBA()
-	charData := xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	var charData xml.CharData
+	if abgr, ok := e.Value.(ABGR); ok {
+		charData = xml.CharData(abgr.String())
+	} else {
+		red, green, blue, alpha := e.Value.RGBA()
+		charData = xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	}
 	return encodeElementWithCharData(encoder, startElement, charData)
 }
 
@@ -466,6 +471,10 @@ func (e *GxAbstractTourPrimitiveElement) Append(children ...Element) *GxAbstract
 	return e
 }
 
+func (e *GxAbstractTourPrimitiveElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxAbstractTourPrimitiveElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:AbstractTourPrimitive"}}
@@ -495,6 +504,10 @@ func (e *GxAnimatedUpdateElement) Append(children ...Element) *GxAnimatedUpdateE
 	return e
 }
 
+func (e *GxAnimatedUpdateElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxAnimatedUpdateElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:AnimatedUpdate"}}
@@ -524,6 +537,10 @@ func (e *GxFlyToElement) Append(children ...Element) *GxFlyToElement {
 	return e
 }
 
+func (e *GxFlyToElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxFlyToElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:FlyTo"}}
@@ -553,6 +570,10 @@ func (e *GxPlaylistElement) Append(children ...Element) *GxPlaylistElement {
 	return e
 }
 
+func (e *GxPlaylistElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxPlaylistElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:Playlist"}}
@@ -582,6 +603,10 @@ func (e *GxSoundCueElement) Append(children ...Element) *GxSoundCueElement {
 	return e
 }
 
+func (e *GxSoundCueElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxSoundCueElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:SoundCue"}}
@@ -611,6 +636,10 @@ func (e *GxTourElement) Append(children ...Element) *GxTourElement {
 	return e
 }
 
+func (e *GxTourElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxTourElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:Tour"}}
@@ -640,6 +669,10 @@ func (e *GxTimeStampElement) Append(children ...Element) *GxTimeStampElement {
 	return e
 }
 
+func (e *GxTimeStampElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxTimeStampElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:TimeStamp"}}
@@ -669,6 +702,10 @@ func (e *GxTimeSpanElement) Append(children ...Element) *GxTimeSpanElement {
 	return e
 }
 
+func (e *GxTimeSpanElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxTimeSpanElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:TimeSpan"}}
@@ -698,6 +735,10 @@ func (e *GxTourControlElement) Append(children ...Element) *GxTourControlElement
 	return e
 }
 
+func (e *GxTourControlElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxTourControlElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:TourControl"}}
@@ -727,6 +768,10 @@ func (e *GxWaitElement) Append(children ...Element) *GxWaitElement {
 	return e
 }
 
+func (e *GxWaitElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxWaitElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:Wait"}}
@@ -756,6 +801,10 @@ func (e *GxLatLonQuadElement) Append(children ...Element) *GxLatLonQuadElement {
 	return e
 }
 
+func (e *GxLatLonQuadElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxLatLonQuadElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:LatLonQuad"}}
@@ -785,6 +834,10 @@ func (e *GxTrackElement) Append(children ...Element) *GxTrackElement {
 	return e
 }
 
+func (e *GxTrackElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxTrackElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:Track"}}
@@ -814,6 +867,10 @@ func (e *GxMultiTrackElement) Append(children ...Element) *GxMultiTrackElement {
 	return e
 }
 
+func (e *GxMultiTrackElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxMultiTrackElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:MultiTrack"}}
@@ -843,6 +900,10 @@ func (e *GxViewerOptionsElement) Append(children ...Element) *GxViewerOptionsEle
 	return e
 }
 
+func (e *GxViewerOptionsElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxViewerOptionsElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "gx:ViewerOptions"}}
@@ -86,6 +86,13 @@ func GxKML(child Element) *GxKMLElement {
 	}
 }
 
+func (e *GxKMLElement) children() []Element {
+	if e.Child == nil {
+		return nil
+	}
+	return []Element{e.Child}
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxKMLElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -161,6 +168,10 @@ func (e *GxSimpleArrayDataElement) Append(children ...Element) *GxSimpleArrayDat
 	return e
 }
 
+func (e *GxSimpleArrayDataElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxSimpleArrayDataElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -199,6 +210,10 @@ func (e *GxSimpleArrayFieldElement) Append(children ...Element) *GxSimpleArrayFi
 	return e
 }
 
+func (e *GxSimpleArrayFieldElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GxSimpleArrayFieldElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
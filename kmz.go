@@ -4,22 +4,45 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
-	"sort"
+	"path"
+	"slices"
+	"strings"
 )
 
 // WriteKMZ writes a KMZ file containing files to w. The values of the files map
 // can be []bytes, strings, *KMLElements, *GxKMLElements, Elements, or
-// io.Readers.
+// io.Readers. See github.com/twpayne/go-kml/v3/kmz.Writer for writing KMZ
+// files incrementally with the root document first.
+//
+// The root document, which is doc.kml if present and otherwise the first KML
+// file in sorted order, is written first, followed by the other files in
+// sorted order. Files are deflated and, as with zip.Writer.Create, have no
+// modification time, so the same files produce the same bytes when written by
+// the same version of Go.
 func WriteKMZ(w io.Writer, files map[string]any) error {
 	names := make([]string, 0, len(files))
 	for name := range files {
 		names = append(names, name)
 	}
-	sort.Strings(names)
+	slices.Sort(names)
+	i := slices.Index(names, "doc.kml")
+	if i < 0 {
+		i = slices.IndexFunc(names, func(name string) bool {
+			return strings.EqualFold(path.Ext(name), ".kml")
+		})
+	}
+	if i > 0 {
+		root := names[i]
+		copy(names[1:i+1], names[:i])
+		names[0] = root
+	}
 
 	zipWriter := zip.NewWriter(w)
 	for _, filename := range names {
-		zipFileWriter, err := zipWriter.Create(filename)
+		zipFileWriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:   filename,
+			Method: zip.Deflate,
+		})
 		if err != nil {
 			return err
 		}
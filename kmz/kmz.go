@@ -0,0 +1,156 @@
+// Package kmz reads and writes KMZ files.
+//
+// A KMZ file is a zip archive containing a root KML document, conventionally
+// named doc.kml, and the files that it references, such as images and models.
+// Google Earth uses the first KML file in the archive as the root document, so
+// Writer always writes the root first.
+//
+// Entries are written in the order in which they are created, with compression
+// methods chosen by extension and modification times taken from
+// Writer.Modified, so the same inputs produce the same bytes when written by
+// the same version of Go.
+package kmz
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// RootName is the name of the root document written by Writer.SetRoot.
+const RootName = "doc.kml"
+
+// Errors.
+var (
+	ErrClosed        = errors.New("kmz: writer closed")
+	ErrDuplicateName = errors.New("kmz: duplicate name")
+	ErrNegativeLimit = errors.New("kmz: negative limit")
+	ErrNoRoot        = errors.New("kmz: no root document")
+	ErrRootWritten   = errors.New("kmz: root document already written")
+)
+
+// storedExts are the extensions of files that are already compressed and so
+// are stored without compression.
+var storedExts = map[string]bool{
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".kmz":  true,
+	".png":  true,
+	".zip":  true,
+}
+
+// A Writer writes a KMZ file. The root document must be written first, either
+// with SetRoot or by creating a KML file. Entries are streamed to the
+// underlying writer as they are written, so each entry must be written
+// completely before the next is created.
+type Writer struct {
+	// Modified is the modification time of entries created with Create and
+	// AddFile. If it is zero then entries have no modification time. It is
+	// never taken from the clock or from the files added, so that output is
+	// reproducible.
+	Modified time.Time
+
+	zipWriter   *zip.Writer
+	names       map[string]bool
+	rootWritten bool
+	closed      bool
+}
+
+// NewWriter returns a new Writer that writes a KMZ file to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		zipWriter: zip.NewWriter(w),
+		names:     make(map[string]bool),
+	}
+}
+
+// AddFile adds the file at filename to the KMZ file as name. Its modification
+// time is w.Modified, not that of the file.
+func (w *Writer) AddFile(name, filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	fileWriter, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fileWriter, file)
+	return err
+}
+
+// Close finishes writing the KMZ file. It does not close the underlying
+// writer. It returns ErrNoRoot if no root document was written.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	if err := w.zipWriter.Close(); err != nil {
+		return err
+	}
+	if !w.rootWritten {
+		return ErrNoRoot
+	}
+	return nil
+}
+
+// Create adds an entry called name to the KMZ file and returns a writer for
+// its contents. Files that are already compressed, such as PNG and JPEG
+// images, are stored and other files are deflated. The entry's modification
+// time is w.Modified. If no root document has been written then name must be a
+// KML file, which becomes the root document.
+func (w *Writer) Create(name string) (io.Writer, error) {
+	method := zip.Deflate
+	if storedExts[strings.ToLower(path.Ext(name))] {
+		method = zip.Store
+	}
+	return w.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   method,
+		Modified: w.Modified,
+	})
+}
+
+// CreateHeader adds an entry described by fileHeader to the KMZ file and
+// returns a writer for its contents, giving full control over the entry's
+// compression method and metadata. If no root document has been written then
+// the entry must be a KML file, which becomes the root document.
+func (w *Writer) CreateHeader(fileHeader *zip.FileHeader) (io.Writer, error) {
+	if w.closed {
+		return nil, ErrClosed
+	}
+	if w.names[fileHeader.Name] {
+		return nil, fmt.Errorf("%s: %w", fileHeader.Name, ErrDuplicateName)
+	}
+	if !w.rootWritten {
+		if !strings.EqualFold(path.Ext(fileHeader.Name), ".kml") {
+			return nil, fmt.Errorf("%s: %w", fileHeader.Name, ErrNoRoot)
+		}
+		w.rootWritten = true
+	}
+	w.names[fileHeader.Name] = true
+	return w.zipWriter.CreateHeader(fileHeader)
+}
+
+// SetRoot writes root to the KMZ file as RootName. It must be called before
+// any other entry is created.
+func (w *Writer) SetRoot(root kml.TopLevelElement) error {
+	if w.rootWritten {
+		return ErrRootWritten
+	}
+	fileWriter, err := w.Create(RootName)
+	if err != nil {
+		return err
+	}
+	return root.Write(fileWriter)
+}
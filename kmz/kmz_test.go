@@ -0,0 +1,111 @@
+package kmz_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/kmz"
+)
+
+func TestWriter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.dae")
+	assert.NoError(t, os.WriteFile(filename, []byte("<COLLADA/>"), 0o666))
+
+	var buffer bytes.Buffer
+	w := kmz.NewWriter(&buffer)
+	assert.NoError(t, w.SetRoot(kml.KML(kml.Placemark(kml.Name("root")))))
+	iconWriter, err := w.Create("files/icon.PNG")
+	assert.NoError(t, err)
+	_, err = iconWriter.Write([]byte("png"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.AddFile("files/model.dae", filename))
+	headerWriter, err := w.CreateHeader(&zip.FileHeader{Name: "files/data.txt", Method: zip.Store})
+	assert.NoError(t, err)
+	_, err = headerWriter.Write([]byte("data"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NoError(t, err)
+	type entry struct {
+		name     string
+		method   uint16
+		contents string
+	}
+	var actual []entry
+	for _, zipFile := range zipReader.File {
+		file, err := zipFile.Open()
+		assert.NoError(t, err)
+		contents, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+		actual = append(actual, entry{
+			name:     zipFile.Name,
+			method:   zipFile.Method,
+			contents: string(contents),
+		})
+	}
+	assert.Equal(t, []entry{
+		{
+			name:     "doc.kml",
+			method:   zip.Deflate,
+			contents: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<kml xmlns="http://www.opengis.net/kml/2.2"><Placemark><name>root</name></Placemark></kml>`,
+		},
+		{name: "files/icon.PNG", method: zip.Store, contents: "png"},
+		{name: "files/model.dae", method: zip.Deflate, contents: "<COLLADA/>"},
+		{name: "files/data.txt", method: zip.Store, contents: "data"},
+	}, actual)
+}
+
+func TestWriterErrors(t *testing.T) {
+	w := kmz.NewWriter(io.Discard)
+	_, err := w.Create("files/icon.png")
+	assert.IsError(t, err, kmz.ErrNoRoot)
+	assert.IsError(t, w.Close(), kmz.ErrNoRoot)
+	assert.IsError(t, w.SetRoot(kml.KML(kml.Document())), kmz.ErrClosed)
+
+	w = kmz.NewWriter(io.Discard)
+	_, err = w.Create("root.kml")
+	assert.NoError(t, err)
+	assert.IsError(t, w.SetRoot(kml.KML(kml.Document())), kmz.ErrRootWritten)
+	_, err = w.Create("root.kml")
+	assert.IsError(t, err, kmz.ErrDuplicateName)
+	_, err = w.Create("other.kml")
+	assert.NoError(t, err)
+	assert.Error(t, w.AddFile("missing.png", filepath.Join(t.TempDir(), "missing.png")))
+	assert.NoError(t, w.Close())
+	assert.IsError(t, w.Close(), kmz.ErrClosed)
+}
+
+func TestWriterReproducible(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.dae")
+	assert.NoError(t, os.WriteFile(filename, []byte("<COLLADA/>"), 0o666))
+	modified := time.Date(2020, time.January, 2, 3, 4, 6, 0, time.UTC)
+
+	write := func() []byte {
+		var buffer bytes.Buffer
+		w := kmz.NewWriter(&buffer)
+		w.Modified = modified
+		assert.NoError(t, w.SetRoot(kml.KML(kml.Placemark(kml.Name("root")))))
+		assert.NoError(t, w.AddFile("files/model.dae", filename))
+		assert.NoError(t, w.Close())
+		return buffer.Bytes()
+	}
+	data := write()
+	assert.NoError(t, os.Chtimes(filename, time.Now(), time.Now()))
+	assert.Equal(t, data, write())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	for _, zipFile := range zipReader.File {
+		assert.True(t, zipFile.Modified.Equal(modified))
+	}
+}
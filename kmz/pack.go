@@ -0,0 +1,258 @@
+package kmz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// DefaultDir is the directory of resources in KMZ files if Packer.Dir is
+// empty.
+const DefaultDir = "files"
+
+// ErrRemote is returned by Packer.Pack for remote URLs when the remote policy
+// is RemoteFail.
+var ErrRemote = errors.New("kmz: remote resource")
+
+// A RemotePolicy determines how a Packer handles references to remote URLs.
+type RemotePolicy int
+
+// Remote policies.
+const (
+	RemoteLeave RemotePolicy = iota // Leave the reference unchanged.
+	RemoteFetch                     // Fetch the resource and bundle it.
+	RemoteFail                      // Return an error.
+)
+
+// A Fetcher fetches remote resources.
+type Fetcher interface {
+	Fetch(ctx context.Context, w io.Writer, rawURL string) error
+}
+
+// A FetcherFunc is a function that implements Fetcher.
+type FetcherFunc func(ctx context.Context, w io.Writer, rawURL string) error
+
+// An HTTPFetcher fetches remote resources over HTTP.
+type HTTPFetcher struct {
+	Client *http.Client // Client, or nil for http.DefaultClient.
+}
+
+// A Packer writes KMZ files containing a root document and the local
+// resources that it references, such as icons, overlay images, and COLLADA
+// models. References are the values of href elements, in Icon, Link, and
+// ItemIcon elements, and targetHref elements, in Model ResourceMaps.
+//
+// Local references are relative paths, absolute paths, and file:// URLs.
+// Relative paths that do not exist locally, which may be relative to the
+// document's eventual location rather than a local file, are left unchanged.
+// References with other schemes, such as data: URLs, and references to the
+// same document, starting with #, are left unchanged.
+type Packer struct {
+	Dir     string       // Directory of resources in KMZ files. Empty means DefaultDir.
+	BaseDir string       // Directory that relative paths are relative to. Empty means the current directory.
+	Remote  RemotePolicy // Policy for http and https URLs.
+	Fetcher Fetcher      // Fetcher for remote URLs, or nil for an HTTPFetcher.
+}
+
+// A resource is a resource to be bundled.
+type resource struct {
+	name     string // Name in the KMZ file.
+	filename string // Local filename, if local.
+	rawURL   string // Remote URL, if remote.
+}
+
+// Fetch implements Fetcher.Fetch.
+func (f FetcherFunc) Fetch(ctx context.Context, w io.Writer, rawURL string) error {
+	return f(ctx, w, rawURL)
+}
+
+// Fetch implements Fetcher.Fetch.
+func (f HTTPFetcher) Fetch(ctx context.Context, w io.Writer, rawURL string) error {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", rawURL, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Pack rewrites the references in root to refer to resources in the KMZ file,
+// writes root to w as the root document, and then writes the resources to w.
+// root is modified in place, but only once all references have been
+// resolved, so root is unchanged if resolving any reference fails. Each
+// resource is written once, however many times it is referenced, in the order
+// in which it is first referenced. Pack does not close w, so further entries
+// can be added.
+func (p *Packer) Pack(ctx context.Context, w *Writer, root kml.TopLevelElement) error {
+	type rewrite struct {
+		value *string
+		name  string
+	}
+	var rewrites []rewrite
+	var resources []*resource
+	resourcesByKey := make(map[string]*resource)
+	names := make(map[string]bool)
+	if err := kml.Walk(root, func(element kml.Element) error {
+		var value *string
+		switch element := element.(type) {
+		case *kml.HrefElement:
+			value = &element.Value
+		case *kml.TargetHrefElement:
+			value = &element.Value
+		default:
+			return nil
+		}
+		r, ok, err := p.resource(*value)
+		switch {
+		case err != nil:
+			return err
+		case !ok:
+			return nil
+		}
+		key := r.filename + "\x00" + r.rawURL
+		if existing, ok := resourcesByKey[key]; ok {
+			rewrites = append(rewrites, rewrite{value: value, name: existing.name})
+			return nil
+		}
+		r.name = uniqueName(path.Join(p.dir(), r.name), names)
+		resourcesByKey[key] = &r
+		resources = append(resources, &r)
+		rewrites = append(rewrites, rewrite{value: value, name: r.name})
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, rewrite := range rewrites {
+		*rewrite.value = rewrite.name
+	}
+
+	if err := w.SetRoot(root); err != nil {
+		return err
+	}
+	fetcher := p.Fetcher
+	if fetcher == nil {
+		fetcher = HTTPFetcher{}
+	}
+	for _, r := range resources {
+		if r.filename != "" {
+			if err := w.AddFile(r.name, r.filename); err != nil {
+				return err
+			}
+			continue
+		}
+		fileWriter, err := w.Create(r.name)
+		if err != nil {
+			return err
+		}
+		if err := fetcher.Fetch(ctx, fileWriter, r.rawURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dir returns the directory of resources.
+func (p *Packer) dir() string {
+	if p.Dir == "" {
+		return DefaultDir
+	}
+	return p.Dir
+}
+
+// resource returns the resource referenced by href and true, or false if href
+// should be left unchanged.
+func (p *Packer) resource(href string) (resource, bool, error) {
+	if href == "" || strings.HasPrefix(href, "#") {
+		return resource{}, false, nil
+	}
+	u, err := url.Parse(href)
+	switch {
+	case err != nil || len(u.Scheme) == 1:
+		// Not a URL, or a Windows path with a drive letter.
+		return p.localResource(href)
+	case u.Scheme == "":
+		filename, err := url.PathUnescape(u.Path)
+		if err != nil {
+			return resource{}, false, err
+		}
+		return p.localResource(filename)
+	case u.Scheme == "file":
+		filename := filepath.FromSlash(u.Path)
+		if len(filename) > 2 && filename[0] == filepath.Separator && filename[2] == ':' {
+			// file:///C:/path on Windows.
+			filename = filename[1:]
+		}
+		return p.localResource(filename)
+	case u.Scheme == "http" || u.Scheme == "https":
+		switch p.Remote {
+		case RemoteFetch:
+			name := path.Base(u.Path)
+			if name == "." || name == "/" {
+				name = "resource"
+			}
+			return resource{name: name, rawURL: href}, true, nil
+		case RemoteFail:
+			return resource{}, false, fmt.Errorf("%s: %w", href, ErrRemote)
+		default:
+			return resource{}, false, nil
+		}
+	default:
+		return resource{}, false, nil
+	}
+}
+
+// localResource returns the local resource at filename, or false if filename
+// is relative and does not exist.
+func (p *Packer) localResource(filename string) (resource, bool, error) {
+	relative := !filepath.IsAbs(filename)
+	if relative {
+		filename = filepath.Join(p.BaseDir, filename)
+	}
+	fileInfo, err := os.Stat(filename)
+	switch {
+	case relative && errors.Is(err, fs.ErrNotExist):
+		return resource{}, false, nil
+	case err != nil:
+		return resource{}, false, err
+	}
+	if !fileInfo.Mode().IsRegular() {
+		return resource{}, false, fmt.Errorf("%s: not a regular file", filename)
+	}
+	return resource{name: filepath.Base(filename), filename: filename}, true, nil
+}
+
+// uniqueName returns name, or name with a numeric suffix before its
+// extension if name is already in names, and adds the result to names.
+func uniqueName(name string, names map[string]bool) string {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	result := name
+	for i := 1; names[result]; i++ {
+		result = stem + "-" + strconv.Itoa(i) + ext
+	}
+	names[result] = true
+	return result
+}
@@ -0,0 +1,234 @@
+package kmz_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/kmz"
+)
+
+func TestPacker(t *testing.T) {
+	baseDir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(baseDir, "a"), 0o777))
+	assert.NoError(t, os.Mkdir(filepath.Join(baseDir, "b"), 0o777))
+	for name, contents := range map[string]string{
+		"a/icon.png":  "a",
+		"b/icon.png":  "b",
+		"model.dae":   "<COLLADA/>",
+		"texture.jpg": "jpg",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(baseDir, name), []byte(contents), 0o666))
+	}
+	absIcon := filepath.Join(baseDir, "b", "icon.png")
+	fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(absIcon)}).String()
+
+	root := kml.KML(kml.Document(
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("a/icon.png")))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href(absIcon)))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href(fileURL)))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("a/icon.png")))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("http://example.com/icon.png")))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("#local")))),
+		kml.Placemark(
+			kml.Model(
+				kml.Link(kml.Href("model.dae")),
+				kml.ResourceMap(
+					kml.Alias(
+						kml.TargetHref("texture.jpg"),
+						kml.SourceHref("texture.jpg"),
+					),
+				),
+			),
+		),
+	))
+
+	var buffer bytes.Buffer
+	w := kmz.NewWriter(&buffer)
+	packer := &kmz.Packer{BaseDir: baseDir}
+	assert.NoError(t, packer.Pack(t.Context(), w, root))
+	assert.NoError(t, w.Close())
+
+	var hrefs []string
+	assert.NoError(t, kml.Walk(root, func(element kml.Element) error {
+		switch element := element.(type) {
+		case *kml.HrefElement:
+			hrefs = append(hrefs, element.Value)
+		case *kml.TargetHrefElement:
+			hrefs = append(hrefs, element.Value)
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{
+		"files/icon.png",
+		"files/icon-1.png",
+		"files/icon-1.png",
+		"files/icon.png",
+		"http://example.com/icon.png",
+		"#local",
+		"files/model.dae",
+		"files/texture.jpg",
+	}, hrefs)
+
+	names, contents := readKMZ(t, buffer.Bytes())
+	assert.Equal(t, []string{
+		"doc.kml",
+		"files/icon.png",
+		"files/icon-1.png",
+		"files/model.dae",
+		"files/texture.jpg",
+	}, names)
+	assert.Equal(t, "a", contents["files/icon.png"])
+	assert.Equal(t, "b", contents["files/icon-1.png"])
+	assert.True(t, strings.Contains(contents["doc.kml"], "<href>files/model.dae</href>"))
+}
+
+func TestPackerDir(t *testing.T) {
+	baseDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "icon.png"), []byte("png"), 0o666))
+	root := kml.KML(kml.Placemark(kml.Style(kml.IconStyle(kml.Icon(kml.Href("icon.png"))))))
+
+	var buffer bytes.Buffer
+	w := kmz.NewWriter(&buffer)
+	packer := &kmz.Packer{Dir: "images", BaseDir: baseDir}
+	assert.NoError(t, packer.Pack(t.Context(), w, root))
+	assert.NoError(t, w.Close())
+
+	names, _ := readKMZ(t, buffer.Bytes())
+	assert.Equal(t, []string{"doc.kml", "images/icon.png"}, names)
+}
+
+func TestPackerErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		href          string
+		absolute      bool
+		remote        kmz.RemotePolicy
+		expectedErrIs error
+	}{
+		{
+			name:          "missing",
+			href:          "missing.png",
+			absolute:      true,
+			expectedErrIs: os.ErrNotExist,
+		},
+		{
+			name:          "remote_fail",
+			href:          "https://example.com/icon.png",
+			remote:        kmz.RemoteFail,
+			expectedErrIs: kmz.ErrRemote,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			baseDir := t.TempDir()
+			assert.NoError(t, os.WriteFile(filepath.Join(baseDir, "icon.png"), []byte("png"), 0o666))
+			href := tc.href
+			if tc.absolute {
+				href = filepath.Join(baseDir, href)
+			}
+			iconHref := kml.Href("icon.png")
+			root := kml.KML(kml.Document(
+				kml.Style(kml.IconStyle(kml.Icon(iconHref))),
+				kml.Style(kml.IconStyle(kml.Icon(kml.Href(href)))),
+			))
+			packer := &kmz.Packer{BaseDir: baseDir, Remote: tc.remote}
+			err := packer.Pack(t.Context(), kmz.NewWriter(io.Discard), root)
+			assert.IsError(t, err, tc.expectedErrIs)
+			assert.Equal(t, "icon.png", iconHref.Value)
+		})
+	}
+}
+
+func TestPackerMissingRelative(t *testing.T) {
+	href := kml.Href("missing.png")
+	root := kml.KML(kml.Placemark(kml.Style(kml.IconStyle(kml.Icon(href)))))
+	var buffer bytes.Buffer
+	w := kmz.NewWriter(&buffer)
+	packer := &kmz.Packer{BaseDir: t.TempDir()}
+	assert.NoError(t, packer.Pack(t.Context(), w, root))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "missing.png", href.Value)
+	names, _ := readKMZ(t, buffer.Bytes())
+	assert.Equal(t, []string{"doc.kml"}, names)
+}
+
+func TestPackerRemoteFetch(t *testing.T) {
+	var rawURLs []string
+	fetcher := kmz.FetcherFunc(func(ctx context.Context, w io.Writer, rawURL string) error {
+		rawURLs = append(rawURLs, rawURL)
+		_, err := io.WriteString(w, rawURL)
+		return err
+	})
+	root := kml.KML(kml.Document(
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("https://a.example.com/icon.png")))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("https://b.example.com/icon.png")))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("https://a.example.com/icon.png")))),
+		kml.Style(kml.IconStyle(kml.Icon(kml.Href("https://c.example.com/")))),
+	))
+
+	var buffer bytes.Buffer
+	w := kmz.NewWriter(&buffer)
+	packer := &kmz.Packer{Remote: kmz.RemoteFetch, Fetcher: fetcher}
+	assert.NoError(t, packer.Pack(t.Context(), w, root))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"https://a.example.com/icon.png",
+		"https://b.example.com/icon.png",
+		"https://c.example.com/",
+	}, rawURLs)
+	names, contents := readKMZ(t, buffer.Bytes())
+	assert.Equal(t, []string{
+		"doc.kml",
+		"files/icon.png",
+		"files/icon-1.png",
+		"files/resource",
+	}, names)
+	assert.Equal(t, "https://b.example.com/icon.png", contents["files/icon-1.png"])
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/icon.png" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("png"))
+	}))
+	defer server.Close()
+
+	var buffer bytes.Buffer
+	assert.NoError(t, kmz.HTTPFetcher{}.Fetch(t.Context(), &buffer, server.URL+"/icon.png"))
+	assert.Equal(t, "png", buffer.String())
+
+	assert.Error(t, kmz.HTTPFetcher{Client: server.Client()}.Fetch(t.Context(), io.Discard, server.URL+"/missing.png"))
+}
+
+func readKMZ(tb testing.TB, data []byte) ([]string, map[string]string) {
+	tb.Helper()
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(tb, err)
+	var names []string
+	contents := make(map[string]string)
+	for _, zipFile := range zipReader.File {
+		file, err := zipFile.Open()
+		assert.NoError(tb, err)
+		fileContents, err := io.ReadAll(file)
+		assert.NoError(tb, err)
+		assert.NoError(tb, file.Close())
+		names = append(names, zipFile.Name)
+		contents[zipFile.Name] = string(fileContents)
+	}
+	return names, contents
+}
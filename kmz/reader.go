@@ -0,0 +1,268 @@
+package kmz
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Default limits.
+const (
+	DefaultMaxEntries = 1024
+	DefaultMaxSize    = 128 << 20
+	DefaultMaxRatio   = 100
+	DefaultMaxDepth   = 2
+)
+
+// Errors wrapped by ReadErrors.
+var (
+	ErrCompressionRatio = errors.New("kmz: compression ratio too high")
+	ErrInvalidName      = errors.New("kmz: invalid name")
+	ErrTooDeep          = errors.New("kmz: nested too deeply")
+	ErrTooLarge         = errors.New("kmz: too large")
+	ErrTooManyEntries   = errors.New("kmz: too many entries")
+)
+
+// Limits limit the resources used reading a KMZ file. The zero value uses the
+// defaults and negative values are invalid. MaxEntries and MaxSize apply to the
+// KMZ file and all the nested KMZ files opened from it together.
+type Limits struct {
+	MaxEntries int   // Maximum total number of entries. Zero means DefaultMaxEntries.
+	MaxSize    int64 // Maximum total uncompressed size, in bytes. Zero means DefaultMaxSize.
+	MaxRatio   int   // Maximum ratio of uncompressed to compressed size of each entry. Zero means DefaultMaxRatio.
+	MaxDepth   int   // Maximum depth of nested KMZ files, including the outermost. Zero means DefaultMaxDepth.
+}
+
+// A ReadError is returned when a KMZ file is rejected because it is malformed
+// or unsafe. Err is one of the errors above, ErrDuplicateName, ErrNoRoot, or
+// an error from archive/zip such as zip.ErrFormat. ReadErrors are caused by
+// the contents of the KMZ file, not by failures to read it, so are suitable
+// for reporting to whoever supplied it.
+type ReadError struct {
+	Name string // Name of the entry, or empty for the whole file.
+	Err  error
+}
+
+// A Reader reads a KMZ file from an untrusted source. NewReader checks the
+// entries' names and sizes before any contents are read, and entries are not
+// read beyond their declared sizes.
+type Reader struct {
+	files  map[string]*zip.File
+	names  []string
+	root   string
+	limits Limits
+	budget *budget
+	depth  int
+	prefix string
+}
+
+// A budget is the number of entries and uncompressed bytes remaining for a KMZ
+// file and all the nested KMZ files opened from it.
+type budget struct {
+	entries int
+	size    int64
+}
+
+// An entryReader reads an entry, returning ReadErrors for invalid contents.
+type entryReader struct {
+	io.ReadCloser
+	name      string
+	remaining int64
+}
+
+// NewReader returns a new Reader that reads the KMZ file of size bytes from r,
+// subject to limits. The root document is the first KML file in the archive,
+// as in Google Earth. It returns ErrNegativeLimit if any of limits is negative.
+func NewReader(r io.ReaderAt, size int64, limits Limits) (*Reader, error) {
+	if limits.MaxEntries < 0 || limits.MaxSize < 0 || limits.MaxRatio < 0 || limits.MaxDepth < 0 {
+		return nil, ErrNegativeLimit
+	}
+	limits = limits.withDefaults()
+	budget := &budget{
+		entries: limits.MaxEntries,
+		size:    limits.MaxSize,
+	}
+	return newReader(r, size, limits, budget, 1, "")
+}
+
+// Names returns the names of the files in r, excluding directories, in
+// archive order.
+func (r *Reader) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Open opens the file called name in r.
+func (r *Reader) Open(name string) (io.ReadCloser, error) {
+	zipFile, ok := r.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: r.prefix + name, Err: fs.ErrNotExist}
+	}
+	readCloser, err := zipFile.Open()
+	if err != nil {
+		return nil, readError(r.prefix+name, err)
+	}
+	return &entryReader{
+		ReadCloser: readCloser,
+		name:       r.prefix + name,
+		remaining:  int64(zipFile.UncompressedSize64), //nolint:gosec
+	}, nil
+}
+
+// OpenKMZ opens the nested KMZ file called name in r, subject to the same
+// limits as r. The nested KMZ file's entries and sizes count towards the same
+// totals as r's. The nested KMZ file is read into memory.
+func (r *Reader) OpenKMZ(name string) (*Reader, error) {
+	if r.depth >= r.limits.MaxDepth {
+		return nil, &ReadError{Name: r.prefix + name, Err: ErrTooDeep}
+	}
+	data, err := r.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(bytes.NewReader(data), int64(len(data)), r.limits, r.budget, r.depth+1, r.prefix+name+"/")
+}
+
+// ReadFile returns the contents of the file called name in r.
+func (r *Reader) ReadFile(name string) ([]byte, error) {
+	readCloser, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer readCloser.Close()
+	return io.ReadAll(readCloser)
+}
+
+// ReadRoot returns the contents of the root document.
+func (r *Reader) ReadRoot() ([]byte, error) {
+	return r.ReadFile(r.root)
+}
+
+// Root returns the name of the root document.
+func (r *Reader) Root() string {
+	return r.root
+}
+
+// Error implements error.Error.
+func (e *ReadError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+	return e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns e.Err.
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// Read implements io.Reader.Read. It counts the bytes read and returns an
+// error if they exceed the entry's declared size, which has already been
+// counted towards the limits.
+func (r *entryReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, readError(r.name, err)
+	}
+	if int64(n) > r.remaining {
+		return 0, &ReadError{Name: r.name, Err: ErrTooLarge}
+	}
+	r.remaining -= int64(n)
+	return n, err
+}
+
+// withDefaults returns l with zero fields replaced by their defaults.
+func (l Limits) withDefaults() Limits {
+	if l.MaxEntries == 0 {
+		l.MaxEntries = DefaultMaxEntries
+	}
+	if l.MaxSize == 0 {
+		l.MaxSize = DefaultMaxSize
+	}
+	if l.MaxRatio == 0 {
+		l.MaxRatio = DefaultMaxRatio
+	}
+	if l.MaxDepth == 0 {
+		l.MaxDepth = DefaultMaxDepth
+	}
+	return l
+}
+
+// newReader returns a new Reader at depth whose names in errors are prefixed
+// with prefix. Its entries and sizes are subtracted from budget if it is
+// valid.
+func newReader(r io.ReaderAt, size int64, limits Limits, budget *budget, depth int, prefix string) (*Reader, error) {
+	zipReader, err := zip.NewReader(r, size)
+	switch {
+	case errors.Is(err, zip.ErrInsecurePath):
+		// Names are checked below.
+	case err != nil:
+		return nil, readError(strings.TrimSuffix(prefix, "/"), err)
+	}
+	if len(zipReader.File) > budget.entries {
+		return nil, &ReadError{Name: strings.TrimSuffix(prefix, "/"), Err: ErrTooManyEntries}
+	}
+
+	reader := &Reader{
+		files:  make(map[string]*zip.File, len(zipReader.File)),
+		limits: limits,
+		budget: budget,
+		depth:  depth,
+		prefix: prefix,
+	}
+	remainingSize := uint64(budget.size) //nolint:gosec
+	for _, zipFile := range zipReader.File {
+		name := zipFile.Name
+		if !validName(name) {
+			return nil, &ReadError{Name: prefix + name, Err: ErrInvalidName}
+		}
+		if _, ok := reader.files[name]; ok {
+			return nil, &ReadError{Name: prefix + name, Err: ErrDuplicateName}
+		}
+		if zipFile.UncompressedSize64 > remainingSize {
+			return nil, &ReadError{Name: prefix + name, Err: ErrTooLarge}
+		}
+		remainingSize -= zipFile.UncompressedSize64
+		if zipFile.UncompressedSize64 > zipFile.CompressedSize64*uint64(limits.MaxRatio) { //nolint:gosec
+			return nil, &ReadError{Name: prefix + name, Err: ErrCompressionRatio}
+		}
+		reader.files[name] = zipFile
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		reader.names = append(reader.names, name)
+		if reader.root == "" && strings.EqualFold(path.Ext(name), ".kml") {
+			reader.root = name
+		}
+	}
+	if reader.root == "" {
+		return nil, &ReadError{Name: strings.TrimSuffix(prefix, "/"), Err: ErrNoRoot}
+	}
+	budget.entries -= len(zipReader.File)
+	budget.size = int64(remainingSize) //nolint:gosec
+	return reader, nil
+}
+
+// readError returns err as a ReadError if it is caused by invalid contents.
+func readError(name string, err error) error {
+	if errors.Is(err, zip.ErrFormat) || errors.Is(err, zip.ErrAlgorithm) || errors.Is(err, zip.ErrChecksum) {
+		return &ReadError{Name: name, Err: err}
+	}
+	return err
+}
+
+// validName returns if name is a relative slash-separated path that stays
+// within the archive when extracted, on any operating system.
+func validName(name string) bool {
+	switch {
+	case strings.ContainsAny(name, "\\\x00"):
+		return false
+	case len(name) >= 2 && name[1] == ':':
+		return false
+	default:
+		return fs.ValidPath(strings.TrimSuffix(name, "/"))
+	}
+}
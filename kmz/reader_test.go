@@ -0,0 +1,268 @@
+package kmz_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3/kmz"
+)
+
+type zipEntry struct {
+	name     string
+	contents string
+}
+
+func TestReader(t *testing.T) {
+	data := newZip(t,
+		zipEntry{name: "files/"},
+		zipEntry{name: "files/icon.png", contents: "png"},
+		zipEntry{name: "root.kml", contents: "<kml/>"},
+		zipEntry{name: "doc.kml", contents: "<kml></kml>"},
+	)
+	r, err := kmz.NewReader(bytes.NewReader(data), int64(len(data)), kmz.Limits{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"files/icon.png", "root.kml", "doc.kml"}, r.Names())
+	assert.Equal(t, "root.kml", r.Root())
+
+	root, err := r.ReadRoot()
+	assert.NoError(t, err)
+	assert.Equal(t, "<kml/>", string(root))
+	icon, err := r.ReadFile("files/icon.png")
+	assert.NoError(t, err)
+	assert.Equal(t, "png", string(icon))
+	_, err = r.ReadFile("missing.png")
+	assert.IsError(t, err, fs.ErrNotExist)
+}
+
+func TestReaderErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		entries       []zipEntry
+		limits        kmz.Limits
+		expectedErrIs error
+		expectedName  string
+	}{
+		{
+			name:          "no_root",
+			entries:       []zipEntry{{name: "icon.png", contents: "png"}},
+			expectedErrIs: kmz.ErrNoRoot,
+		},
+		{
+			name: "too_many_entries",
+			entries: []zipEntry{
+				{name: "doc.kml", contents: "<kml/>"},
+				{name: "a.png", contents: "a"},
+				{name: "b.png", contents: "b"},
+			},
+			limits:        kmz.Limits{MaxEntries: 2},
+			expectedErrIs: kmz.ErrTooManyEntries,
+		},
+		{
+			name: "too_large",
+			entries: []zipEntry{
+				{name: "doc.kml", contents: "<kml/>"},
+				{name: "a.png", contents: "0123456789"},
+			},
+			limits:        kmz.Limits{MaxSize: 10},
+			expectedErrIs: kmz.ErrTooLarge,
+			expectedName:  "a.png",
+		},
+		{
+			name: "compression_ratio",
+			entries: []zipEntry{
+				{name: "doc.kml", contents: "<kml>" + strings.Repeat(" ", 1<<20) + "</kml>"},
+			},
+			expectedErrIs: kmz.ErrCompressionRatio,
+			expectedName:  "doc.kml",
+		},
+		{
+			name: "duplicate_name",
+			entries: []zipEntry{
+				{name: "doc.kml", contents: "<kml/>"},
+				{name: "doc.kml", contents: "<kml/>"},
+			},
+			expectedErrIs: kmz.ErrDuplicateName,
+			expectedName:  "doc.kml",
+		},
+		{
+			name:          "parent",
+			entries:       []zipEntry{{name: "../doc.kml", contents: "<kml/>"}},
+			expectedErrIs: kmz.ErrInvalidName,
+			expectedName:  "../doc.kml",
+		},
+		{
+			name:          "nested_parent",
+			entries:       []zipEntry{{name: "files/../../doc.kml", contents: "<kml/>"}},
+			expectedErrIs: kmz.ErrInvalidName,
+			expectedName:  "files/../../doc.kml",
+		},
+		{
+			name:          "absolute",
+			entries:       []zipEntry{{name: "/doc.kml", contents: "<kml/>"}},
+			expectedErrIs: kmz.ErrInvalidName,
+			expectedName:  "/doc.kml",
+		},
+		{
+			name:          "backslash",
+			entries:       []zipEntry{{name: "..\\doc.kml", contents: "<kml/>"}},
+			expectedErrIs: kmz.ErrInvalidName,
+			expectedName:  "..\\doc.kml",
+		},
+		{
+			name:          "drive_letter",
+			entries:       []zipEntry{{name: "C:/doc.kml", contents: "<kml/>"}},
+			expectedErrIs: kmz.ErrInvalidName,
+			expectedName:  "C:/doc.kml",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := newZip(t, tc.entries...)
+			_, err := kmz.NewReader(bytes.NewReader(data), int64(len(data)), tc.limits)
+			assert.IsError(t, err, tc.expectedErrIs)
+			var readError *kmz.ReadError
+			assert.True(t, errors.As(err, &readError))
+			assert.Equal(t, tc.expectedName, readError.Name)
+		})
+	}
+}
+
+func TestReaderNegativeLimits(t *testing.T) {
+	data := newZip(t, zipEntry{name: "doc.kml", contents: "<kml></kml>"})
+	for _, limits := range []kmz.Limits{
+		{MaxEntries: -1},
+		{MaxSize: -1},
+		{MaxRatio: -1},
+		{MaxDepth: -1},
+	} {
+		_, err := kmz.NewReader(bytes.NewReader(data), int64(len(data)), limits)
+		assert.IsError(t, err, kmz.ErrNegativeLimit)
+	}
+}
+
+func TestReaderFormatErrors(t *testing.T) {
+	data := []byte("not a zip file")
+	_, err := kmz.NewReader(bytes.NewReader(data), int64(len(data)), kmz.Limits{})
+	assert.IsError(t, err, zip.ErrFormat)
+	var readError *kmz.ReadError
+	assert.True(t, errors.As(err, &readError))
+
+	// An entry that declares a smaller size than its contents.
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	contents := []byte("<kml></kml>")
+	fileWriter, err := zipWriter.CreateRaw(&zip.FileHeader{
+		Name:               "doc.kml",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(contents),
+		CompressedSize64:   uint64(len(contents)),
+		UncompressedSize64: 5,
+	})
+	assert.NoError(t, err)
+	_, err = fileWriter.Write(contents)
+	assert.NoError(t, err)
+	assert.NoError(t, zipWriter.Close())
+	r, err := kmz.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), kmz.Limits{})
+	assert.NoError(t, err)
+	_, err = r.ReadRoot()
+	assert.IsError(t, err, zip.ErrFormat)
+	assert.True(t, errors.As(err, &readError))
+	assert.Equal(t, "doc.kml", readError.Name)
+}
+
+func TestReaderOpenKMZ(t *testing.T) {
+	innermost := newZip(t, zipEntry{name: "doc.kml", contents: "<kml>innermost</kml>"})
+	inner := newZip(t,
+		zipEntry{name: "doc.kml", contents: "<kml>inner</kml>"},
+		zipEntry{name: "files/innermost.kmz", contents: string(innermost)},
+	)
+	outer := newZip(t,
+		zipEntry{name: "doc.kml", contents: "<kml>outer</kml>"},
+		zipEntry{name: "files/inner.kmz", contents: string(inner)},
+	)
+
+	r, err := kmz.NewReader(bytes.NewReader(outer), int64(len(outer)), kmz.Limits{})
+	assert.NoError(t, err)
+	innerReader, err := r.OpenKMZ("files/inner.kmz")
+	assert.NoError(t, err)
+	root, err := innerReader.ReadRoot()
+	assert.NoError(t, err)
+	assert.Equal(t, "<kml>inner</kml>", string(root))
+	_, err = innerReader.OpenKMZ("files/innermost.kmz")
+	assert.IsError(t, err, kmz.ErrTooDeep)
+	var readError *kmz.ReadError
+	assert.True(t, errors.As(err, &readError))
+	assert.Equal(t, "files/inner.kmz/files/innermost.kmz", readError.Name)
+
+	r, err = kmz.NewReader(bytes.NewReader(outer), int64(len(outer)), kmz.Limits{MaxDepth: 3})
+	assert.NoError(t, err)
+	innerReader, err = r.OpenKMZ("files/inner.kmz")
+	assert.NoError(t, err)
+	innermostReader, err := innerReader.OpenKMZ("files/innermost.kmz")
+	assert.NoError(t, err)
+	root, err = innermostReader.ReadRoot()
+	assert.NoError(t, err)
+	assert.Equal(t, "<kml>innermost</kml>", string(root))
+
+	r, err = kmz.NewReader(bytes.NewReader(outer), int64(len(outer)), kmz.Limits{MaxDepth: 1})
+	assert.NoError(t, err)
+	_, err = r.OpenKMZ("files/inner.kmz")
+	assert.IsError(t, err, kmz.ErrTooDeep)
+}
+
+func TestReaderOpenKMZLimits(t *testing.T) {
+	inner := newZip(t,
+		zipEntry{name: "doc.kml", contents: "<kml/>"},
+		zipEntry{name: "a.png", contents: "0123456789"},
+	)
+	outer := newZip(t,
+		zipEntry{name: "doc.kml", contents: "<kml/>"},
+		zipEntry{name: "files/inner.kmz", contents: string(inner)},
+	)
+
+	// The inner KMZ file is within the size limit on its own, but not once
+	// the outer KMZ file is counted.
+	outerSize := int64(len("<kml/>") + len(inner))
+	r, err := kmz.NewReader(bytes.NewReader(outer), int64(len(outer)), kmz.Limits{MaxSize: outerSize + 10})
+	assert.NoError(t, err)
+	_, err = r.OpenKMZ("files/inner.kmz")
+	assert.IsError(t, err, kmz.ErrTooLarge)
+	var readError *kmz.ReadError
+	assert.True(t, errors.As(err, &readError))
+	assert.Equal(t, "files/inner.kmz/a.png", readError.Name)
+
+	r, err = kmz.NewReader(bytes.NewReader(outer), int64(len(outer)), kmz.Limits{MaxSize: outerSize + 16})
+	assert.NoError(t, err)
+	_, err = r.OpenKMZ("files/inner.kmz")
+	assert.NoError(t, err)
+	_, err = r.OpenKMZ("files/inner.kmz")
+	assert.IsError(t, err, kmz.ErrTooLarge)
+
+	r, err = kmz.NewReader(bytes.NewReader(outer), int64(len(outer)), kmz.Limits{MaxEntries: 3})
+	assert.NoError(t, err)
+	_, err = r.OpenKMZ("files/inner.kmz")
+	assert.IsError(t, err, kmz.ErrTooManyEntries)
+	assert.True(t, errors.As(err, &readError))
+	assert.Equal(t, "files/inner.kmz", readError.Name)
+}
+
+func newZip(tb testing.TB, entries ...zipEntry) []byte {
+	tb.Helper()
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		fileWriter, err := zipWriter.Create(entry.name)
+		assert.NoError(tb, err)
+		_, err = io.WriteString(fileWriter, entry.contents)
+		assert.NoError(tb, err)
+	}
+	assert.NoError(tb, zipWriter.Close())
+	return buffer.Bytes()
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
 
 	"github.com/twpayne/go-kml/v3"
 )
@@ -51,3 +54,29 @@ func ExampleWriteKMZ() {
 	// <?xml version="1.0" encoding="UTF-8"?>
 	// <kml xmlns="http://www.opengis.net/kml/2.2"><Placemark><name>Zürich</name><Point><coordinates>8.541111,47.374444</coordinates></Point></Placemark></kml>
 }
+
+func TestWriteKMZOrder(t *testing.T) {
+	files := map[string]any{
+		"a.png":          []byte("png"),
+		"a/x.kml":        kml.KML(kml.Document()),
+		"doc.kml":        kml.KML(kml.Document()),
+		"files/b.kml":    kml.KML(kml.Document()),
+		"files/c.jpg":    "jpg",
+		"files/icon.gif": bytes.NewReader([]byte("gif")),
+	}
+	var buffer bytes.Buffer
+	assert.NoError(t, kml.WriteKMZ(&buffer, files))
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NoError(t, err)
+	var names []string
+	for _, zipFile := range zipReader.File {
+		names = append(names, zipFile.Name)
+	}
+	assert.Equal(t, []string{"doc.kml", "a.png", "a/x.kml", "files/b.kml", "files/c.jpg", "files/icon.gif"}, names)
+
+	files["files/icon.gif"] = bytes.NewReader([]byte("gif"))
+	var otherBuffer bytes.Buffer
+	assert.NoError(t, kml.WriteKMZ(&otherBuffer, files))
+	assert.Equal(t, buffer.Bytes(), otherBuffer.Bytes())
+}
@@ -0,0 +1,187 @@
+// Package legend renders legends as PNG images for display in ScreenOverlays.
+//
+// Legends are drawn using only the standard library and a built-in 5x7 bitmap
+// font that covers printable ASCII characters.
+package legend
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/internal/bitmapfont"
+	"github.com/twpayne/go-kml/v3/ramp"
+)
+
+// DefaultScale is the scale of legends if Legend.Scale is zero.
+const DefaultScale = 2
+
+// Layout, in unscaled pixels.
+const (
+	padding      = 4
+	swatchWidth  = 12
+	swatchHeight = bitmapfont.Height + 2
+	swatchGap    = 4
+	rowGap       = 2
+	titleGap     = 4
+)
+
+// Default colors.
+var (
+	DefaultBackground = kml.ABGR(0xc0ffffff)
+	DefaultForeground = kml.ABGR(0xff000000)
+)
+
+// A Corner is a corner of the screen.
+type Corner int
+
+// Corners.
+const (
+	CornerBottomLeft Corner = iota
+	CornerBottomRight
+	CornerTopLeft
+	CornerTopRight
+)
+
+// An Entry is a colored swatch and its label.
+type Entry struct {
+	Color color.Color
+	Label string
+}
+
+// A Legend is a legend with an optional title and a list of entries from top
+// to bottom.
+//
+// Colors are interpreted as by kml.Color, so colors with straight alpha, such
+// as kml.ABGRs, appear the same in the legend as in KML styles.
+type Legend struct {
+	Title      string
+	Entries    []Entry
+	Background color.Color // Background color, or nil for DefaultBackground.
+	Foreground color.Color // Color of text and swatch outlines, or nil for DefaultForeground.
+	Scale      int         // Size of each font pixel, in pixels. Zero means DefaultScale.
+	Corner     Corner      // Corner of the screen where the legend is displayed.
+	Margin     int         // Distance from the corner of the screen, in pixels.
+}
+
+// FromRamp returns a Legend with the given title and an entry for each bucket
+// of r, with the highest values at the top.
+func FromRamp(r ramp.Ramp, title string) *Legend {
+	n := r.NumBuckets()
+	entries := make([]Entry, 0, n)
+	for bucket := n - 1; bucket >= 0; bucket-- {
+		entries = append(entries, Entry{
+			Color: r.BucketColor(bucket),
+			Label: r.BucketName(bucket),
+		})
+	}
+	return &Legend{
+		Title:   title,
+		Entries: entries,
+	}
+}
+
+// Bounds returns the bounds of the image of l.
+func (l *Legend) Bounds() image.Rectangle {
+	width := 0
+	for _, entry := range l.Entries {
+		width = max(width, swatchWidth+swatchGap+bitmapfont.TextWidth(entry.Label))
+	}
+	width = max(width, bitmapfont.TextWidth(l.Title))
+	height := len(l.Entries)*(swatchHeight+rowGap) - rowGap
+	if len(l.Entries) == 0 {
+		height = 0
+	}
+	if l.Title != "" {
+		height += bitmapfont.Height
+		if len(l.Entries) > 0 {
+			height += titleGap
+		}
+	}
+	scale := l.scale()
+	return image.Rect(0, 0, (width+2*padding)*scale, (height+2*padding)*scale)
+}
+
+// Image returns the image of l.
+func (l *Legend) Image() *image.NRGBA {
+	scale := l.scale()
+	foreground := nrgba(l.Foreground, DefaultForeground)
+	img := image.NewNRGBA(l.Bounds())
+	draw.Draw(img, img.Bounds(), image.NewUniform(nrgba(l.Background, DefaultBackground)), image.Point{}, draw.Src)
+
+	x, y := padding, padding
+	if l.Title != "" {
+		bitmapfont.Draw(img, image.Pt(x*scale, y*scale), l.Title, foreground, scale)
+		y += bitmapfont.Height + titleGap
+	}
+	for _, entry := range l.Entries {
+		swatch := image.Rect(x, y, x+swatchWidth, y+swatchHeight)
+		outline := image.Rect(swatch.Min.X*scale, swatch.Min.Y*scale, swatch.Max.X*scale, swatch.Max.Y*scale)
+		draw.Draw(img, outline, image.NewUniform(foreground), image.Point{}, draw.Over)
+		draw.Draw(img, outline.Inset(scale), image.NewUniform(nrgba(entry.Color, color.Transparent)), image.Point{}, draw.Src)
+		bitmapfont.Draw(img, image.Pt((x+swatchWidth+swatchGap)*scale, (y+1)*scale), entry.Label, foreground, scale)
+		y += swatchHeight + rowGap
+	}
+	return img
+}
+
+// PNG returns the image of l encoded as a PNG.
+func (l *Legend) PNG() ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, l.Image()); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Render returns the image of l encoded as a PNG and a ScreenOverlay that
+// displays it from href, ready to be added to a KMZ file with the PNG at href.
+func (l *Legend) Render(href string, children ...kml.Element) ([]byte, *kml.ScreenOverlayElement, error) {
+	data, err := l.PNG()
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, l.ScreenOverlay(href, children...), nil
+}
+
+// ScreenOverlay returns a ScreenOverlay that displays the image of l from href
+// at its actual size in l.Corner, with the given children.
+func (l *Legend) ScreenOverlay(href string, children ...kml.Element) *kml.ScreenOverlayElement {
+	size := l.Bounds().Size()
+	margin := float64(l.Margin)
+	overlayXY := kml.Vec2{XUnits: kml.UnitsFraction, YUnits: kml.UnitsFraction}
+	screenXY := kml.Vec2{X: margin, Y: margin, XUnits: kml.UnitsPixels, YUnits: kml.UnitsPixels}
+	if l.Corner == CornerBottomRight || l.Corner == CornerTopRight {
+		overlayXY.X = 1
+		screenXY.XUnits = kml.UnitsInsetPixels
+	}
+	if l.Corner == CornerTopLeft || l.Corner == CornerTopRight {
+		overlayXY.Y = 1
+		screenXY.YUnits = kml.UnitsInsetPixels
+	}
+	return kml.ScreenOverlay(children...).Append(
+		kml.Icon(kml.Href(href)),
+		kml.OverlayXY(overlayXY),
+		kml.ScreenXY(screenXY),
+		kml.Size(kml.Vec2{X: float64(size.X), Y: float64(size.Y), XUnits: kml.UnitsPixels, YUnits: kml.UnitsPixels}),
+	)
+}
+
+// scale returns the scale of l.
+func (l *Legend) scale() int {
+	if l.Scale <= 0 {
+		return DefaultScale
+	}
+	return l.Scale
+}
+
+// nrgba returns c as a color.NRGBA, or defaultColor if c is nil.
+func nrgba(c, defaultColor color.Color) color.NRGBA {
+	if c == nil {
+		c = defaultColor
+	}
+	return kml.ABGRModel.Convert(c).(kml.ABGR).NRGBA() //nolint:forcetypeassert
+}
@@ -0,0 +1,127 @@
+package legend_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/legend"
+	"github.com/twpayne/go-kml/v3/ramp"
+)
+
+func TestLegend(t *testing.T) {
+	l := &legend.Legend{
+		Title: "Legend",
+		Entries: []legend.Entry{
+			{Color: kml.ABGR(0xff0000ff), Label: "A"},
+			{Color: kml.ABGR(0x80ff0000), Label: "Low"},
+		},
+	}
+	assert.Equal(t, image.Rect(0, 0, 86, 78), l.Bounds())
+
+	data, overlay, err := l.Render("files/legend.png")
+	assert.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, l.Bounds(), img.Bounds())
+	nrgbaModel := color.NRGBAModel
+	assert.Equal(t, color.Color(color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xc0}), nrgbaModel.Convert(img.At(0, 0)))
+	assert.Equal(t, color.Color(color.NRGBA{A: 0xff}), nrgbaModel.Convert(img.At(8, 30)))
+	assert.Equal(t, color.Color(color.NRGBA{R: 0xff, A: 0xff}), nrgbaModel.Convert(img.At(12, 34)))
+	assert.Equal(t, color.Color(color.NRGBA{B: 0xff, A: 0x80}), nrgbaModel.Convert(img.At(12, 56)))
+	// The top left pixel of the L in Low.
+	assert.Equal(t, color.Color(color.NRGBA{A: 0xff}), nrgbaModel.Convert(img.At(40, 54)))
+
+	actual, err := xml.Marshal(overlay)
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<ScreenOverlay>`+
+		`<Icon><href>files/legend.png</href></Icon>`+
+		`<overlayXY x="0" y="0" xunits="fraction" yunits="fraction"></overlayXY>`+
+		`<screenXY x="0" y="0" xunits="pixels" yunits="pixels"></screenXY>`+
+		`<size x="86" y="78" xunits="pixels" yunits="pixels"></size>`+
+		`</ScreenOverlay>`,
+		string(actual))
+}
+
+func TestScreenOverlay(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		corner   legend.Corner
+		expected string
+	}{
+		{
+			name:   "bottom_left",
+			corner: legend.CornerBottomLeft,
+			expected: `<overlayXY x="0" y="0" xunits="fraction" yunits="fraction"></overlayXY>` +
+				`<screenXY x="10" y="10" xunits="pixels" yunits="pixels"></screenXY>`,
+		},
+		{
+			name:   "bottom_right",
+			corner: legend.CornerBottomRight,
+			expected: `<overlayXY x="1" y="0" xunits="fraction" yunits="fraction"></overlayXY>` +
+				`<screenXY x="10" y="10" xunits="insetPixels" yunits="pixels"></screenXY>`,
+		},
+		{
+			name:   "top_left",
+			corner: legend.CornerTopLeft,
+			expected: `<overlayXY x="0" y="1" xunits="fraction" yunits="fraction"></overlayXY>` +
+				`<screenXY x="10" y="10" xunits="pixels" yunits="insetPixels"></screenXY>`,
+		},
+		{
+			name:   "top_right",
+			corner: legend.CornerTopRight,
+			expected: `<overlayXY x="1" y="1" xunits="fraction" yunits="fraction"></overlayXY>` +
+				`<screenXY x="10" y="10" xunits="insetPixels" yunits="insetPixels"></screenXY>`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &legend.Legend{
+				Title:  "x",
+				Scale:  1,
+				Corner: tc.corner,
+				Margin: 10,
+			}
+			actual, err := xml.Marshal(l.ScreenOverlay("legend.png", kml.Name("Legend")))
+			assert.NoError(t, err)
+			assert.Equal(t, ``+
+				`<ScreenOverlay>`+
+				`<name>Legend</name>`+
+				`<Icon><href>legend.png</href></Icon>`+
+				tc.expected+
+				`<size x="13" y="15" xunits="pixels" yunits="pixels"></size>`+
+				`</ScreenOverlay>`,
+				string(actual))
+		})
+	}
+}
+
+func TestFromRamp(t *testing.T) {
+	r := ramp.Ramp{
+		Palette: kml.Palette{0xff0000ff, 0xff00ff00, 0xffff0000},
+		Min:     0,
+		Max:     300,
+		Stepped: true,
+	}
+	l := legend.FromRamp(r, "Altitude")
+	assert.Equal(t, "Altitude", l.Title)
+	assert.Equal(t, []legend.Entry{
+		{Color: kml.ABGR(0xffff0000), Label: "200 to 300"},
+		{Color: kml.ABGR(0xff00ff00), Label: "100 to 200"},
+		{Color: kml.ABGR(0xff0000ff), Label: "0 to 100"},
+	}, l.Entries)
+
+	data, err := l.PNG()
+	assert.NoError(t, err)
+	var buffer bytes.Buffer
+	assert.NoError(t, kml.WriteKMZ(&buffer, map[string]any{
+		"doc.kml":    kml.KML(l.ScreenOverlay("legend.png")),
+		"legend.png": data,
+	}))
+}
@@ -0,0 +1,103 @@
+package nmea
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// SchemaID is the id of the Schema returned by Schema.
+const SchemaID = "nmea"
+
+// LineString returns a LineString of fixes with the given children. Altitudes
+// are absolute if every fix has an altitude and clamped to the ground
+// otherwise.
+func LineString(fixes []Fix, children ...kml.Element) *kml.LineStringElement {
+	hasAlt := hasAlt(fixes)
+	cs := make([]kml.Coordinate, len(fixes))
+	for i, fix := range fixes {
+		cs[i] = coordinate(fix, hasAlt)
+	}
+	lineString := kml.LineString(children...)
+	if hasAlt {
+		lineString.Append(kml.AltitudeMode(kml.AltitudeModeAbsolute))
+	}
+	return lineString.Append(kml.Coordinates(cs...))
+}
+
+// Schema returns a Schema describing the arrays in the ExtendedData of the
+// gx:Track returned by Track.
+func Schema() *kml.SchemaElement {
+	return kml.Schema(SchemaID,
+		kml.GxSimpleArrayField("speed", "float", kml.DisplayName("Speed (m/s)")),
+		kml.GxSimpleArrayField("course", "float", kml.DisplayName("Course (degrees)")),
+		kml.GxSimpleArrayField("hdop", "float", kml.DisplayName("HDOP")),
+	)
+}
+
+// Track returns a gx:Track of fixes with the given children and ExtendedData
+// containing the speed, course, and HDOP of each fix, as described by Schema.
+// Unknown values are empty. Altitudes are as for LineString.
+func Track(fixes []Fix, children ...kml.Element) *kml.GxTrackElement {
+	hasAlt := hasAlt(fixes)
+	n := len(fixes)
+	whens := make([]kml.Element, 0, n)
+	coords := make([]kml.Element, 0, n)
+	speeds := make([]kml.Element, 0, n)
+	courses := make([]kml.Element, 0, n)
+	hdops := make([]kml.Element, 0, n)
+	for _, fix := range fixes {
+		whens = append(whens, kml.When(fix.Time))
+		coords = append(coords, kml.GxCoord(coordinate(fix, hasAlt)))
+		speeds = append(speeds, value(fix.Speed, 2))
+		courses = append(courses, value(fix.Course, 1))
+		hdops = append(hdops, value(fix.HDOP, 1))
+	}
+	track := kml.GxTrack(children...)
+	if hasAlt {
+		track.Append(kml.AltitudeMode(kml.AltitudeModeAbsolute))
+	}
+	track.Append(whens...)
+	track.Append(coords...)
+	return track.Append(
+		kml.ExtendedData(
+			kml.SchemaData("#"+SchemaID,
+				kml.GxSimpleArrayData("speed", speeds...),
+				kml.GxSimpleArrayData("course", courses...),
+				kml.GxSimpleArrayData("hdop", hdops...),
+			),
+		),
+	)
+}
+
+// coordinate returns the coordinate of fix.
+func coordinate(fix Fix, hasAlt bool) kml.Coordinate {
+	c := kml.Coordinate{Lon: fix.Lon, Lat: fix.Lat}
+	if hasAlt {
+		c.Alt = fix.Alt
+	}
+	return c
+}
+
+// hasAlt returns if every fix has an altitude.
+func hasAlt(fixes []Fix) bool {
+	if len(fixes) == 0 {
+		return false
+	}
+	for _, fix := range fixes {
+		if math.IsNaN(fix.Alt) {
+			return false
+		}
+	}
+	return true
+}
+
+// value returns a gx:value element containing x with prec decimal places, or
+// an empty value if x is NaN.
+func value(x float64, prec int) *kml.GxValueElement {
+	if math.IsNaN(x) {
+		return kml.GxValue("")
+	}
+	return kml.GxValue(strconv.FormatFloat(x, 'f', prec, 64))
+}
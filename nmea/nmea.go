@@ -0,0 +1,435 @@
+// Package nmea parses NMEA 0183 sentences and converts them to KML.
+//
+// GGA, RMC, GSA, and VTG sentences from any talker are used. Sentences are
+// grouped into fixes by their time: GGA and RMC sentences with a new time
+// start a new fix, and GSA and VTG sentences update the current fix. A fix
+// whose time is more than 12 hours before the previous fix's is taken to be on
+// the next day, so small backwards steps from out of order sentences do not
+// move the rest of the fixes.
+//
+// See https://gpsd.gitlab.io/gpsd/NMEA.html.
+package nmea
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// knot is one knot in meters per second.
+const knot = 1852.0 / 3600
+
+// maxBackwardsStep is the largest backwards step in fix times that is not
+// taken to be a day rollover.
+const maxBackwardsStep = 12 * time.Hour
+
+var (
+	errInvalidChecksum = errors.New("invalid checksum")
+	errMissingChecksum = errors.New("missing checksum")
+	errNoFix           = errors.New("no fix")
+)
+
+// A Policy determines how sentences are handled.
+type Policy int
+
+// Policies.
+const (
+	PolicyDrop  Policy = iota // Drop the sentence or fix.
+	PolicyError               // Return an error.
+	PolicyKeep                // Keep the sentence or fix.
+)
+
+// A T parses NMEA 0183 sentences.
+type T struct {
+	// Invalid is the policy for invalid sentences, including sentences with
+	// invalid checksums. If it is PolicyKeep then sentences with invalid
+	// checksums are used and other invalid sentences are dropped.
+	Invalid Policy
+	// NoFix is the policy for fixes that do not have a valid position
+	// according to any of their sentences. Fixes without any position are
+	// always dropped.
+	NoFix Policy
+	// RequireChecksum treats sentences without a checksum as invalid.
+	RequireChecksum bool
+	// Date is the date of fixes before the first RMC sentence. Only its
+	// calendar date in its own location is used. NMEA times are UTC.
+	Date time.Time
+}
+
+// Default drops invalid sentences and fixes without a valid position.
+var Default = T{}
+
+// A Fix is a position at a point in time.
+type Fix struct {
+	Time       time.Time
+	Lat        float64
+	Lon        float64
+	Alt        float64 // Altitude above mean sea level in meters, or NaN if unknown.
+	Speed      float64 // Speed over ground in meters per second, or NaN if unknown.
+	Course     float64 // Course over ground in degrees true, or NaN if unknown.
+	HDOP       float64 // Horizontal dilution of precision, or NaN if unknown.
+	Satellites int     // Number of satellites used, or zero if unknown.
+	Valid      bool    // Whether the fix has a valid position.
+}
+
+// A parser holds the state of a parse.
+type parser struct {
+	T
+	fixes       []Fix
+	fix         *Fix
+	timeOfDay   time.Duration
+	hasPosition bool
+	date        time.Time
+	lastTime    time.Time
+}
+
+// Parse parses the NMEA 0183 sentences in r and returns the fixes. Lines that
+// do not start with $ are ignored, as are unsupported sentences.
+func (t T) Parse(r io.Reader) ([]Fix, error) {
+	p := &parser{
+		T:    t,
+		date: time.Date(t.Date.Year(), t.Date.Month(), t.Date.Day(), 0, 0, 0, 0, time.UTC),
+	}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if err := p.parseLine(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("nmea: line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := p.flush(); err != nil {
+		return nil, fmt.Errorf("nmea: line %d: %w", lineNumber, err)
+	}
+	return p.fixes, nil
+}
+
+// parseLine parses a single line.
+func (p *parser) parseLine(line string) error {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "$") {
+		return nil
+	}
+	fields, err := p.fields(line)
+	switch {
+	case err == nil:
+	case p.Invalid == PolicyError:
+		return err
+	default:
+		return nil
+	}
+	if len(fields[0]) < 5 {
+		return p.invalid(fmt.Errorf("invalid address: %q", fields[0]))
+	}
+	switch fields[0][len(fields[0])-3:] {
+	case "GGA":
+		err = p.parseGGA(fields[1:])
+	case "GSA":
+		err = p.parseGSA(fields[1:])
+	case "RMC":
+		err = p.parseRMC(fields[1:])
+	case "VTG":
+		err = p.parseVTG(fields[1:])
+	}
+	if err != nil {
+		return p.invalid(err)
+	}
+	return nil
+}
+
+// fields validates the checksum of line and returns its fields.
+func (p *parser) fields(line string) ([]string, error) {
+	data, checksum, ok := strings.Cut(line[1:], "*")
+	switch {
+	case ok:
+		expected, err := strconv.ParseUint(checksum, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum: %q", checksum)
+		}
+		var actual byte
+		for i := range len(data) {
+			actual ^= data[i]
+		}
+		if actual != byte(expected) && p.Invalid != PolicyKeep {
+			return nil, errInvalidChecksum
+		}
+	case p.RequireChecksum:
+		return nil, errMissingChecksum
+	}
+	return strings.Split(data, ","), nil
+}
+
+// invalid handles an invalid sentence according to p.Invalid.
+func (p *parser) invalid(err error) error {
+	if p.Invalid == PolicyError {
+		return err
+	}
+	return nil
+}
+
+// parseGGA parses the fields of a GGA sentence.
+func (p *parser) parseGGA(fields []string) error {
+	if len(fields) < 9 {
+		return errors.New("GGA sentence too short")
+	}
+	timeOfDay, err := parseTimeOfDay(fields[0])
+	if err != nil {
+		return err
+	}
+	quality, err := parseInt(fields[5])
+	if err != nil {
+		return err
+	}
+	satellites, err := parseInt(fields[6])
+	if err != nil {
+		return err
+	}
+	hdop, err := parseFloat(fields[7])
+	if err != nil {
+		return err
+	}
+	alt, err := parseFloat(fields[8])
+	if err != nil {
+		return err
+	}
+	if err := p.startFix(timeOfDay); err != nil {
+		return err
+	}
+	if err := p.setPosition(fields[1:5], quality != 0); err != nil {
+		return err
+	}
+	p.fix.Satellites = satellites
+	p.fix.HDOP = hdop
+	p.fix.Alt = alt
+	return nil
+}
+
+// parseGSA parses the fields of a GSA sentence.
+func (p *parser) parseGSA(fields []string) error {
+	if len(fields) < 16 {
+		return errors.New("GSA sentence too short")
+	}
+	if p.fix == nil {
+		return nil
+	}
+	if fields[1] == "1" {
+		p.fix.Valid = false
+	}
+	hdop, err := parseFloat(fields[15])
+	if err != nil {
+		return err
+	}
+	if !math.IsNaN(hdop) {
+		p.fix.HDOP = hdop
+	}
+	return nil
+}
+
+// parseRMC parses the fields of an RMC sentence.
+func (p *parser) parseRMC(fields []string) error {
+	if len(fields) < 9 {
+		return errors.New("RMC sentence too short")
+	}
+	timeOfDay, err := parseTimeOfDay(fields[0])
+	if err != nil {
+		return err
+	}
+	speed, err := parseFloat(fields[6])
+	if err != nil {
+		return err
+	}
+	course, err := parseFloat(fields[7])
+	if err != nil {
+		return err
+	}
+	if fields[8] != "" {
+		date, err := time.Parse("020106", fields[8])
+		if err != nil {
+			return fmt.Errorf("invalid date: %q", fields[8])
+		}
+		if p.fix != nil && p.timeOfDay == timeOfDay {
+			p.fix.Time = date.Add(timeOfDay)
+		}
+		p.date = date
+	}
+	if err := p.startFix(timeOfDay); err != nil {
+		return err
+	}
+	if err := p.setPosition(fields[2:6], fields[1] == "A"); err != nil {
+		return err
+	}
+	p.fix.Speed = speed * knot
+	p.fix.Course = course
+	return nil
+}
+
+// parseVTG parses the fields of a VTG sentence.
+func (p *parser) parseVTG(fields []string) error {
+	if len(fields) < 7 {
+		return errors.New("VTG sentence too short")
+	}
+	if p.fix == nil || len(fields) > 8 && fields[8] == "N" {
+		return nil
+	}
+	course, err := parseFloat(fields[0])
+	if err != nil {
+		return err
+	}
+	speed, err := parseFloat(fields[6])
+	if err != nil {
+		return err
+	}
+	if !math.IsNaN(course) {
+		p.fix.Course = course
+	}
+	if !math.IsNaN(speed) {
+		p.fix.Speed = speed / 3.6
+	}
+	return nil
+}
+
+// startFix starts a new fix if timeOfDay is different from the time of the
+// current fix.
+func (p *parser) startFix(timeOfDay time.Duration) error {
+	if p.fix != nil && timeOfDay == p.timeOfDay {
+		return nil
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	fixTime := p.date.Add(timeOfDay)
+	for !p.lastTime.IsZero() && p.lastTime.Sub(fixTime) > maxBackwardsStep {
+		fixTime = fixTime.AddDate(0, 0, 1)
+	}
+	p.fix = &Fix{
+		Time:   fixTime,
+		Alt:    math.NaN(),
+		Speed:  math.NaN(),
+		Course: math.NaN(),
+		HDOP:   math.NaN(),
+		Valid:  true,
+	}
+	p.timeOfDay = timeOfDay
+	p.hasPosition = false
+	return nil
+}
+
+// setPosition sets the position of the current fix from latitude, N/S,
+// longitude, and E/W fields.
+func (p *parser) setPosition(fields []string, valid bool) error {
+	if !valid {
+		p.fix.Valid = false
+	}
+	if fields[0] == "" || fields[2] == "" {
+		return nil
+	}
+	lat, err := parseAngle(fields[0], 2, fields[1], "N", "S")
+	if err != nil {
+		return err
+	}
+	lon, err := parseAngle(fields[2], 3, fields[3], "E", "W")
+	if err != nil {
+		return err
+	}
+	p.fix.Lat, p.fix.Lon = lat, lon
+	p.hasPosition = true
+	return nil
+}
+
+// flush adds the current fix, if any, to the fixes according to p.NoFix.
+func (p *parser) flush() error {
+	if p.fix == nil {
+		return nil
+	}
+	fix := p.fix
+	p.fix = nil
+	if !p.hasPosition {
+		fix.Valid = false
+	}
+	if !fix.Valid {
+		switch p.NoFix {
+		case PolicyDrop:
+			return nil
+		case PolicyError:
+			return errNoFix
+		case PolicyKeep:
+			if !p.hasPosition {
+				return nil
+			}
+		}
+	}
+	p.fixes = append(p.fixes, *fix)
+	p.lastTime = fix.Time
+	return nil
+}
+
+// parseAngle parses an angle in the form DDMM.mmmm with the given number of
+// degree digits and hemisphere.
+func parseAngle(s string, degreeDigits int, hemisphere, positive, negative string) (float64, error) {
+	if len(s) < degreeDigits+2 {
+		return 0, fmt.Errorf("invalid angle: %q", s)
+	}
+	degrees, err := strconv.Atoi(s[:degreeDigits])
+	if err != nil {
+		return 0, fmt.Errorf("invalid angle: %q", s)
+	}
+	minutes, err := strconv.ParseFloat(s[degreeDigits:], 64)
+	if err != nil || minutes >= 60 {
+		return 0, fmt.Errorf("invalid angle: %q", s)
+	}
+	angle := float64(degrees) + minutes/60
+	switch hemisphere {
+	case positive:
+		return angle, nil
+	case negative:
+		return -angle, nil
+	default:
+		return 0, fmt.Errorf("invalid hemisphere: %q", hemisphere)
+	}
+}
+
+// parseFloat parses an optional float, returning NaN if s is empty.
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return math.NaN(), nil
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %q", s)
+	}
+	return value, nil
+}
+
+// parseInt parses an optional int, returning zero if s is empty.
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %q", s)
+	}
+	return value, nil
+}
+
+// parseTimeOfDay parses a time of day in the form HHMMSS.ss.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if len(s) < 6 {
+		return 0, fmt.Errorf("invalid time: %q", s)
+	}
+	hour, err1 := strconv.Atoi(s[0:2])
+	minute, err2 := strconv.Atoi(s[2:4])
+	second, err3 := strconv.ParseFloat(s[4:], 64)
+	if errors.Join(err1, err2, err3) != nil || hour > 23 || minute > 59 || second >= 61 {
+		return 0, fmt.Errorf("invalid time: %q", s)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second*float64(time.Second)), nil
+}
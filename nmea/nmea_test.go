@@ -0,0 +1,149 @@
+package nmea_test
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3/nmea"
+)
+
+const testNMEA = "" +
+	"$GPGGA,235958.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*64\r\n" +
+	"$GPRMC,235958.00,A,4807.038,N,01131.000,E,022.4,084.4,150724,003.1,W*43\r\n" +
+	"$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*39\r\n" +
+	"$GPVTG,084.4,T,,M,022.4,N,041.5,K,A*01\r\n" +
+	"$GPGGA,235959.00,4807.048,N,01131.010,E,1,08,0.9,546.4,M,46.9,M,,*61\r\n" +
+	"$GPGGA,235959.00,4807.048,N,01131.010,E,1,08,0.9,546.4,M,46.9,M,,*60\r\n" +
+	"garbage\r\n" +
+	"$GPGGA,000000.00,,,,,0,00,,,M,,M,,*48\r\n" +
+	"$GPGGA,000001.00,4807.058,N,01131.020,E,1,08,1.0,547.4,M,46.9,M,,*6B\r\n" +
+	"$GPRMC,000001.00,A,4807.058,N,01131.020,E,010.0,090.0,160724,,*3A\r\n"
+
+const noFixNMEA = "" +
+	"$GNGGA,120000,4600.000,S,00800.000,W,0,00,,,M,,M,,*45\r\n" +
+	"$GNRMC,120000,V,4600.000,S,00800.000,W,,,010124,,*14\r\n"
+
+func TestParse(t *testing.T) {
+	fixes, err := nmea.Default.Parse(strings.NewReader(testNMEA))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(fixes))
+
+	assert.Equal(t, time.Date(2024, 7, 15, 23, 59, 58, 0, time.UTC), fixes[0].Time)
+	assertInDelta(t, 48+7.038/60, fixes[0].Lat, 1e-9)
+	assertInDelta(t, 11+31.0/60, fixes[0].Lon, 1e-9)
+	assertInDelta(t, 545.4, fixes[0].Alt, 1e-9)
+	assertInDelta(t, 41.5/3.6, fixes[0].Speed, 1e-9)
+	assertInDelta(t, 84.4, fixes[0].Course, 1e-9)
+	assertInDelta(t, 1.3, fixes[0].HDOP, 1e-9)
+	assert.Equal(t, 8, fixes[0].Satellites)
+	assert.True(t, fixes[0].Valid)
+
+	assert.Equal(t, time.Date(2024, 7, 15, 23, 59, 59, 0, time.UTC), fixes[1].Time)
+	assert.True(t, math.IsNaN(fixes[1].Speed))
+	assertInDelta(t, 0.9, fixes[1].HDOP, 1e-9)
+
+	assert.Equal(t, time.Date(2024, 7, 16, 0, 0, 1, 0, time.UTC), fixes[2].Time)
+	assertInDelta(t, 10*1852.0/3600, fixes[2].Speed, 1e-9)
+}
+
+func TestParsePolicies(t *testing.T) {
+	_, err := nmea.T{Invalid: nmea.PolicyError}.Parse(strings.NewReader(testNMEA))
+	assert.EqualError(t, err, "nmea: line 5: invalid checksum")
+
+	fixes, err := nmea.T{Invalid: nmea.PolicyKeep}.Parse(strings.NewReader(testNMEA))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(fixes))
+
+	fixes, err = nmea.T{RequireChecksum: true}.Parse(strings.NewReader("$GPGGA,235958.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(fixes))
+
+	fixes, err = nmea.Default.Parse(strings.NewReader(noFixNMEA))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(fixes))
+
+	_, err = nmea.T{NoFix: nmea.PolicyError}.Parse(strings.NewReader(noFixNMEA))
+	assert.EqualError(t, err, "nmea: line 2: no fix")
+
+	fixes, err = nmea.T{NoFix: nmea.PolicyKeep}.Parse(strings.NewReader(noFixNMEA))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixes))
+	assert.False(t, fixes[0].Valid)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), fixes[0].Time)
+	assertInDelta(t, -46, fixes[0].Lat, 1e-9)
+	assertInDelta(t, -8, fixes[0].Lon, 1e-9)
+
+	date := time.Date(2024, 7, 16, 5, 0, 0, 0, time.FixedZone("UTC+10", 10*60*60))
+	fixes, err = nmea.T{Date: date}.Parse(strings.NewReader("$GPGGA,120000.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fixes))
+	assert.Equal(t, time.Date(2024, 7, 16, 12, 0, 0, 0, time.UTC), fixes[0].Time)
+}
+
+func TestParseBackwardsStep(t *testing.T) {
+	fixes, err := nmea.T{Date: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)}.Parse(strings.NewReader("" +
+		"$GPGGA,120000.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,\r\n" +
+		"$GPGGA,120005.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,\r\n" +
+		"$GPGGA,120003.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,\r\n" +
+		"$GPGGA,120010.00,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,\r\n",
+	))
+	assert.NoError(t, err)
+	var times []time.Time
+	for _, fix := range fixes {
+		times = append(times, fix.Time)
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 15, 12, 0, 5, 0, time.UTC),
+		time.Date(2024, 7, 15, 12, 0, 3, 0, time.UTC),
+		time.Date(2024, 7, 15, 12, 0, 10, 0, time.UTC),
+	}, times)
+}
+
+func TestTrack(t *testing.T) {
+	fixes, err := nmea.Default.Parse(strings.NewReader(testNMEA))
+	assert.NoError(t, err)
+
+	actual, err := xml.Marshal(nmea.LineString(fixes))
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<LineString>`+
+		`<altitudeMode>absolute</altitudeMode>`+
+		`<coordinates>11.516666666666667,48.1173,545.4 11.516833333333333,48.117466666666665,546.4 11.517,48.11763333333333,547.4</coordinates>`+
+		`</LineString>`,
+		string(actual))
+
+	actual, err = xml.Marshal(nmea.Track(fixes))
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<gx:Track>`+
+		`<altitudeMode>absolute</altitudeMode>`+
+		`<when>2024-07-15T23:59:58Z</when>`+
+		`<when>2024-07-15T23:59:59Z</when>`+
+		`<when>2024-07-16T00:00:01Z</when>`+
+		`<gx:coord>11.516666666666667 48.1173 545.4</gx:coord>`+
+		`<gx:coord>11.516833333333333 48.117466666666665 546.4</gx:coord>`+
+		`<gx:coord>11.517 48.11763333333333 547.4</gx:coord>`+
+		`<ExtendedData>`+
+		`<SchemaData schemaUrl="#nmea">`+
+		`<gx:SimpleArrayData name="speed"><gx:value>11.53</gx:value><gx:value></gx:value><gx:value>5.14</gx:value></gx:SimpleArrayData>`+
+		`<gx:SimpleArrayData name="course"><gx:value>84.4</gx:value><gx:value></gx:value><gx:value>90.0</gx:value></gx:SimpleArrayData>`+
+		`<gx:SimpleArrayData name="hdop"><gx:value>1.3</gx:value><gx:value>0.9</gx:value><gx:value>1.0</gx:value></gx:SimpleArrayData>`+
+		`</SchemaData>`+
+		`</ExtendedData>`+
+		`</gx:Track>`,
+		string(actual))
+}
+
+func assertInDelta(tb testing.TB, expected, actual, delta float64) {
+	tb.Helper()
+	if math.Abs(expected-actual) <= delta {
+		return
+	}
+	tb.Fatalf("Expected %f to be within %f of %f", actual, delta, expected)
+}
@@ -234,8 +234,13 @@ func BgColor(value color.Color) *BgColorElement {
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *BgColorElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "bgColor"}}
-	red, green, blue, alpha := e.Value.RGBA()
-	charData := xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	var charData xml.CharData
+	if abgr, ok := e.Value.(ABGR); ok {
+		charData = xml.CharData(abgr.String())
+	} else {
+		red, green, blue, alpha := e.Value.RGBA()
+		charData = xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	}
 	return encodeElementWithCharData(encoder, startElement, charData)
 }
 
@@ -273,8 +278,13 @@ func Color(value color.Color) *ColorElement {
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ColorElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "color"}}
-	red, green, blue, alpha := e.Value.RGBA()
-	charData := xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	var charData xml.CharData
+	if abgr, ok := e.Value.(ABGR); ok {
+		charData = xml.CharData(abgr.String())
+	} else {
+		red, green, blue, alpha := e.Value.RGBA()
+		charData = xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	}
 	return encodeElementWithCharData(encoder, startElement, charData)
 }
 
@@ -1500,8 +1510,13 @@ func TextColor(value color.Color) *TextColorElement {
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *TextColorElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "textColor"}}
-	red, green, blue, alpha := e.Value.RGBA()
-	charData := xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	var charData xml.CharData
+	if abgr, ok := e.Value.(ABGR); ok {
+		charData = xml.CharData(abgr.String())
+	} else {
+		red, green, blue, alpha := e.Value.RGBA()
+		charData = xml.CharData(fmt.Sprintf("%02x%02x%02x%02x", alpha/256, blue/256, green/256, red/256))
+	}
 	return encodeElementWithCharData(encoder, startElement, charData)
 }
 
@@ -1800,6 +1815,10 @@ func (e *LookAtElement) Append(children ...Element) *LookAtElement {
 	return e
 }
 
+func (e *LookAtElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LookAtElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LookAt"}}
@@ -1829,6 +1848,10 @@ func (e *CameraElement) Append(children ...Element) *CameraElement {
 	return e
 }
 
+func (e *CameraElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *CameraElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Camera"}}
@@ -1858,6 +1881,10 @@ func (e *MetadataElement) Append(children ...Element) *MetadataElement {
 	return e
 }
 
+func (e *MetadataElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *MetadataElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Metadata"}}
@@ -1887,6 +1914,10 @@ func (e *ExtendedDataElement) Append(children ...Element) *ExtendedDataElement {
 	return e
 }
 
+func (e *ExtendedDataElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ExtendedDataElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ExtendedData"}}
@@ -1916,6 +1947,10 @@ func (e *NetworkLinkControlElement) Append(children ...Element) *NetworkLinkCont
 	return e
 }
 
+func (e *NetworkLinkControlElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *NetworkLinkControlElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "NetworkLinkControl"}}
@@ -1945,6 +1980,10 @@ func (e *DocumentElement) Append(children ...Element) *DocumentElement {
 	return e
 }
 
+func (e *DocumentElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *DocumentElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Document"}}
@@ -1974,6 +2013,10 @@ func (e *FolderElement) Append(children ...Element) *FolderElement {
 	return e
 }
 
+func (e *FolderElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *FolderElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Folder"}}
@@ -2003,6 +2046,10 @@ func (e *PlacemarkElement) Append(children ...Element) *PlacemarkElement {
 	return e
 }
 
+func (e *PlacemarkElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *PlacemarkElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Placemark"}}
@@ -2032,6 +2079,10 @@ func (e *NetworkLinkElement) Append(children ...Element) *NetworkLinkElement {
 	return e
 }
 
+func (e *NetworkLinkElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *NetworkLinkElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "NetworkLink"}}
@@ -2061,6 +2112,10 @@ func (e *RegionElement) Append(children ...Element) *RegionElement {
 	return e
 }
 
+func (e *RegionElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *RegionElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Region"}}
@@ -2090,6 +2145,10 @@ func (e *LatLonAltBoxElement) Append(children ...Element) *LatLonAltBoxElement {
 	return e
 }
 
+func (e *LatLonAltBoxElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LatLonAltBoxElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LatLonAltBox"}}
@@ -2119,6 +2178,10 @@ func (e *LODElement) Append(children ...Element) *LODElement {
 	return e
 }
 
+func (e *LODElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LODElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Lod"}}
@@ -2148,6 +2211,10 @@ func (e *IconElement) Append(children ...Element) *IconElement {
 	return e
 }
 
+func (e *IconElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *IconElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Icon"}}
@@ -2177,6 +2244,10 @@ func (e *LinkElement) Append(children ...Element) *LinkElement {
 	return e
 }
 
+func (e *LinkElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LinkElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Link"}}
@@ -2206,6 +2277,10 @@ func (e *URLElement) Append(children ...Element) *URLElement {
 	return e
 }
 
+func (e *URLElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *URLElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Url"}}
@@ -2235,6 +2310,10 @@ func (e *MultiGeometryElement) Append(children ...Element) *MultiGeometryElement
 	return e
 }
 
+func (e *MultiGeometryElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *MultiGeometryElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "MultiGeometry"}}
@@ -2264,6 +2343,10 @@ func (e *PointElement) Append(children ...Element) *PointElement {
 	return e
 }
 
+func (e *PointElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *PointElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Point"}}
@@ -2293,6 +2376,10 @@ func (e *LineStringElement) Append(children ...Element) *LineStringElement {
 	return e
 }
 
+func (e *LineStringElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LineStringElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LineString"}}
@@ -2322,6 +2409,10 @@ func (e *LinearRingElement) Append(children ...Element) *LinearRingElement {
 	return e
 }
 
+func (e *LinearRingElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LinearRingElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LinearRing"}}
@@ -2351,6 +2442,10 @@ func (e *PolygonElement) Append(children ...Element) *PolygonElement {
 	return e
 }
 
+func (e *PolygonElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *PolygonElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Polygon"}}
@@ -2380,6 +2475,10 @@ func (e *OuterBoundaryIsElement) Append(children ...Element) *OuterBoundaryIsEle
 	return e
 }
 
+func (e *OuterBoundaryIsElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *OuterBoundaryIsElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "outerBoundaryIs"}}
@@ -2409,6 +2508,10 @@ func (e *InnerBoundaryIsElement) Append(children ...Element) *InnerBoundaryIsEle
 	return e
 }
 
+func (e *InnerBoundaryIsElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *InnerBoundaryIsElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "innerBoundaryIs"}}
@@ -2438,6 +2541,10 @@ func (e *ModelElement) Append(children ...Element) *ModelElement {
 	return e
 }
 
+func (e *ModelElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ModelElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Model"}}
@@ -2467,6 +2574,10 @@ func (e *LocationElement) Append(children ...Element) *LocationElement {
 	return e
 }
 
+func (e *LocationElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LocationElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Location"}}
@@ -2496,6 +2607,10 @@ func (e *OrientationElement) Append(children ...Element) *OrientationElement {
 	return e
 }
 
+func (e *OrientationElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *OrientationElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Orientation"}}
@@ -2525,6 +2640,10 @@ func (e *ResourceMapElement) Append(children ...Element) *ResourceMapElement {
 	return e
 }
 
+func (e *ResourceMapElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ResourceMapElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ResourceMap"}}
@@ -2554,6 +2673,10 @@ func (e *AliasElement) Append(children ...Element) *AliasElement {
 	return e
 }
 
+func (e *AliasElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *AliasElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Alias"}}
@@ -2583,6 +2706,10 @@ func (e *GroundOverlayElement) Append(children ...Element) *GroundOverlayElement
 	return e
 }
 
+func (e *GroundOverlayElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *GroundOverlayElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "GroundOverlay"}}
@@ -2612,6 +2739,10 @@ func (e *LatLonBoxElement) Append(children ...Element) *LatLonBoxElement {
 	return e
 }
 
+func (e *LatLonBoxElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LatLonBoxElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LatLonBox"}}
@@ -2641,6 +2772,10 @@ func (e *ScreenOverlayElement) Append(children ...Element) *ScreenOverlayElement
 	return e
 }
 
+func (e *ScreenOverlayElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ScreenOverlayElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ScreenOverlay"}}
@@ -2670,6 +2805,10 @@ func (e *PhotoOverlayElement) Append(children ...Element) *PhotoOverlayElement {
 	return e
 }
 
+func (e *PhotoOverlayElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *PhotoOverlayElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "PhotoOverlay"}}
@@ -2699,6 +2838,10 @@ func (e *ViewVolumeElement) Append(children ...Element) *ViewVolumeElement {
 	return e
 }
 
+func (e *ViewVolumeElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ViewVolumeElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ViewVolume"}}
@@ -2728,6 +2871,10 @@ func (e *ImagePyramidElement) Append(children ...Element) *ImagePyramidElement {
 	return e
 }
 
+func (e *ImagePyramidElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ImagePyramidElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ImagePyramid"}}
@@ -2757,6 +2904,10 @@ func (e *PairElement) Append(children ...Element) *PairElement {
 	return e
 }
 
+func (e *PairElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *PairElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Pair"}}
@@ -2786,6 +2937,10 @@ func (e *IconStyleElement) Append(children ...Element) *IconStyleElement {
 	return e
 }
 
+func (e *IconStyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *IconStyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "IconStyle"}}
@@ -2815,6 +2970,10 @@ func (e *LabelStyleElement) Append(children ...Element) *LabelStyleElement {
 	return e
 }
 
+func (e *LabelStyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LabelStyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LabelStyle"}}
@@ -2844,6 +3003,10 @@ func (e *LineStyleElement) Append(children ...Element) *LineStyleElement {
 	return e
 }
 
+func (e *LineStyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *LineStyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "LineStyle"}}
@@ -2873,6 +3036,10 @@ func (e *PolyStyleElement) Append(children ...Element) *PolyStyleElement {
 	return e
 }
 
+func (e *PolyStyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *PolyStyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "PolyStyle"}}
@@ -2902,6 +3069,10 @@ func (e *BalloonStyleElement) Append(children ...Element) *BalloonStyleElement {
 	return e
 }
 
+func (e *BalloonStyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *BalloonStyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "BalloonStyle"}}
@@ -2931,6 +3102,10 @@ func (e *ListStyleElement) Append(children ...Element) *ListStyleElement {
 	return e
 }
 
+func (e *ListStyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ListStyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ListStyle"}}
@@ -2960,6 +3135,10 @@ func (e *ItemIconElement) Append(children ...Element) *ItemIconElement {
 	return e
 }
 
+func (e *ItemIconElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ItemIconElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "ItemIcon"}}
@@ -2989,6 +3168,10 @@ func (e *TimeStampElement) Append(children ...Element) *TimeStampElement {
 	return e
 }
 
+func (e *TimeStampElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *TimeStampElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "TimeStamp"}}
@@ -3018,6 +3201,10 @@ func (e *TimeSpanElement) Append(children ...Element) *TimeSpanElement {
 	return e
 }
 
+func (e *TimeSpanElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *TimeSpanElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "TimeSpan"}}
@@ -3047,6 +3234,10 @@ func (e *UpdateElement) Append(children ...Element) *UpdateElement {
 	return e
 }
 
+func (e *UpdateElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *UpdateElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Update"}}
@@ -3076,6 +3267,10 @@ func (e *CreateElement) Append(children ...Element) *CreateElement {
 	return e
 }
 
+func (e *CreateElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *CreateElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Create"}}
@@ -3105,6 +3300,10 @@ func (e *DeleteElement) Append(children ...Element) *DeleteElement {
 	return e
 }
 
+func (e *DeleteElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *DeleteElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Delete"}}
@@ -3134,6 +3333,10 @@ func (e *ChangeElement) Append(children ...Element) *ChangeElement {
 	return e
 }
 
+func (e *ChangeElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ChangeElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Change"}}
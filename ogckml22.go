@@ -144,6 +144,10 @@ func (e *DataElement) Append(children ...Element) *DataElement {
 	return e
 }
 
+func (e *DataElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *DataElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -167,6 +171,13 @@ func KML(child Element) *KMLElement {
 	}
 }
 
+func (e *KMLElement) children() []Element {
+	if e.Child == nil {
+		return nil
+	}
+	return []Element{e.Child}
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *KMLElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -240,6 +251,10 @@ func (e *ModelScaleElement) Append(children ...Element) *ModelScaleElement {
 	return e
 }
 
+func (e *ModelScaleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *ModelScaleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Scale"}}
@@ -281,6 +296,10 @@ func (e *SchemaElement) Append(children ...Element) *SchemaElement {
 	return e
 }
 
+func (e *SchemaElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *SchemaElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -336,6 +355,10 @@ func (e *SchemaDataElement) Append(children ...Element) *SchemaDataElement {
 	return e
 }
 
+func (e *SchemaDataElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *SchemaDataElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -400,6 +423,10 @@ func (e *SimpleFieldElement) Append(children ...Element) *SimpleFieldElement {
 	return e
 }
 
+func (e *SimpleFieldElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *SimpleFieldElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{
@@ -476,6 +503,10 @@ func (e *StyleElement) Append(children ...Element) *StyleElement {
 	return e
 }
 
+func (e *StyleElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *StyleElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "Style"}}
@@ -533,6 +564,10 @@ func (e *StyleMapElement) Append(children ...Element) *StyleMapElement {
 	return e
 }
 
+func (e *StyleMapElement) children() []Element {
+	return e.Children
+}
+
 // MarshalXML implements encoding/xml.Marshaler.MarshalXML.
 func (e *StyleMapElement) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
 	startElement := xml.StartElement{Name: xml.Name{Local: "StyleMap"}}
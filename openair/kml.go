@@ -0,0 +1,163 @@
+package openair
+
+import (
+	"image/color"
+	"slices"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// classColors are the colors of airspace classes.
+var classColors = map[string]color.RGBA{
+	"A":   {R: 0xc0, G: 0x00, B: 0x00, A: 0xff},
+	"B":   {R: 0x00, G: 0x00, B: 0xc0, A: 0xff},
+	"C":   {R: 0x00, G: 0x00, B: 0xc0, A: 0xff},
+	"CTR": {R: 0x80, G: 0x00, B: 0xc0, A: 0xff},
+	"D":   {R: 0x00, G: 0x60, B: 0xff, A: 0xff},
+	"E":   {R: 0x00, G: 0x80, B: 0x00, A: 0xff},
+	"F":   {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"G":   {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"GP":  {R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+	"P":   {R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+	"Q":   {R: 0xff, G: 0x80, B: 0x00, A: 0xff},
+	"R":   {R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+	"RMZ": {R: 0x00, G: 0xa0, B: 0xa0, A: 0xff},
+	"TMZ": {R: 0x00, G: 0xa0, B: 0xa0, A: 0xff},
+	"W":   {R: 0xff, G: 0xc0, B: 0x00, A: 0xff},
+}
+
+// defaultClassColor is the color of airspace classes not in classColors.
+var defaultClassColor = color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xff}
+
+// Document returns a Document containing a shared Style for each airspace
+// class and a Folder for each class containing its airspaces. Classes are
+// sorted by name and airspaces are in their original order. children are
+// added to the Document before the styles.
+func Document(airspaces []*Airspace, children ...kml.Element) *kml.DocumentElement {
+	airspacesByClass := make(map[string][]*Airspace)
+	for _, airspace := range airspaces {
+		airspacesByClass[airspace.Class] = append(airspacesByClass[airspace.Class], airspace)
+	}
+	classes := make([]string, 0, len(airspacesByClass))
+	for class := range airspacesByClass {
+		classes = append(classes, class)
+	}
+	slices.Sort(classes)
+
+	document := kml.Document(children...)
+	for _, class := range classes {
+		document.Append(ClassStyle(class))
+	}
+	for _, class := range classes {
+		folder := kml.Folder(kml.Name(class))
+		for _, airspace := range airspacesByClass[class] {
+			folder.Append(airspace.Placemark())
+		}
+		document.Append(folder)
+	}
+	return document
+}
+
+// ClassStyle returns a shared Style for airspaces of the given class.
+func ClassStyle(class string) *kml.StyleElement {
+	lineColor, ok := classColors[strings.ToUpper(class)]
+	if !ok {
+		lineColor = defaultClassColor
+	}
+	polyColor := lineColor
+	polyColor.A = 0x40
+	return kml.SharedStyle(classStyleID(class),
+		kml.LineStyle(
+			kml.Color(lineColor),
+			kml.Width(2),
+		),
+		kml.PolyStyle(
+			kml.Color(polyColor),
+		),
+	)
+}
+
+// Placemark returns a Placemark for a, using the shared Style returned by
+// ClassStyle. If the floor of a is the surface then the geometry is a Polygon
+// at the ceiling extruded to the ground. Otherwise it is a MultiGeometry of
+// separate Polygons for the ceiling and the floor, each with its own altitude
+// mode. KML geometries have a single altitude mode, so Polygons for the walls
+// between them are only added if the ceiling and floor have the same altitude
+// mode.
+func (a *Airspace) Placemark() *kml.PlacemarkElement {
+	var geometry kml.Element
+	if a.Floor.Reference == ReferenceSurface {
+		geometry = ringPolygon(a.Ring, a.Ceiling, true)
+	} else {
+		multiGeometry := kml.MultiGeometry(
+			ringPolygon(a.Ring, a.Ceiling, false),
+			ringPolygon(a.Ring, a.Floor, false),
+		)
+		if altitudeMode(a.Floor) == altitudeMode(a.Ceiling) {
+			for i := 1; i < len(a.Ring); i++ {
+				c1, c2 := a.Ring[i-1], a.Ring[i]
+				multiGeometry.Append(
+					kml.Polygon(
+						kml.AltitudeMode(altitudeMode(a.Ceiling)),
+						kml.OuterBoundaryIs(
+							kml.LinearRing(
+								kml.Coordinates(
+									kml.Coordinate{Lon: c1.Lon, Lat: c1.Lat, Alt: a.Floor.Value},
+									kml.Coordinate{Lon: c1.Lon, Lat: c1.Lat, Alt: a.Ceiling.Value},
+									kml.Coordinate{Lon: c2.Lon, Lat: c2.Lat, Alt: a.Ceiling.Value},
+									kml.Coordinate{Lon: c2.Lon, Lat: c2.Lat, Alt: a.Floor.Value},
+									kml.Coordinate{Lon: c1.Lon, Lat: c1.Lat, Alt: a.Floor.Value},
+								),
+							),
+						),
+					),
+				)
+			}
+		}
+		geometry = multiGeometry
+	}
+	return kml.Placemark(
+		kml.Name(a.Name),
+		kml.Description(a.Class+" "+a.Floor.Text+" - "+a.Ceiling.Text),
+		kml.StyleURL("#"+classStyleID(a.Class)),
+		geometry,
+	)
+}
+
+// altitudeMode returns the KML altitude mode for altitude.
+func altitudeMode(altitude Altitude) kml.AltitudeModeEnum {
+	switch altitude.Reference {
+	case ReferenceAGL:
+		return kml.AltitudeModeRelativeToGround
+	case ReferenceSurface:
+		return kml.AltitudeModeClampToGround
+	default:
+		return kml.AltitudeModeAbsolute
+	}
+}
+
+// classStyleID returns the id of the shared Style for class.
+func classStyleID(class string) string {
+	return "openair-" + strings.ToLower(strings.ReplaceAll(class, " ", "-"))
+}
+
+// ringPolygon returns a Polygon of ring at altitude.
+func ringPolygon(ring []kml.Coordinate, altitude Altitude, extrude bool) *kml.PolygonElement {
+	cs := make([]kml.Coordinate, len(ring))
+	for i, c := range ring {
+		cs[i] = kml.Coordinate{Lon: c.Lon, Lat: c.Lat, Alt: altitude.Value}
+	}
+	polygon := kml.Polygon()
+	if extrude {
+		polygon.Append(kml.Extrude(true))
+	}
+	return polygon.Append(
+		kml.AltitudeMode(altitudeMode(altitude)),
+		kml.OuterBoundaryIs(
+			kml.LinearRing(
+				kml.Coordinates(cs...),
+			),
+		),
+	)
+}
@@ -0,0 +1,328 @@
+// Package openair parses OpenAir airspace files and converts them to KML.
+//
+// See http://www.winpilot.com/usersguide/userairspace.asp.
+package openair
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+// Unit conversions.
+const (
+	foot         = 0.3048
+	nauticalMile = 1852
+)
+
+// UnlimitedAlt is the altitude, in meters, used for unlimited ceilings.
+const UnlimitedAlt = 60000 * foot
+
+var (
+	errNoCenter = errors.New("arc or circle without center")
+
+	altitudeRx = regexp.MustCompile(`\A(\d+(?:\.\d+)?)\s*(FT|F|M)?\s*(MSL|AMSL|ALT|AGL|AGND|ASFC|GND|SFC)?\z`)
+	flRx       = regexp.MustCompile(`\AFL\s*(\d+)\z`)
+)
+
+// A Reference is the reference of an Altitude.
+type Reference int
+
+// References.
+const (
+	ReferenceMSL Reference = iota
+	ReferenceAGL
+	ReferenceFL
+	ReferenceSurface
+	ReferenceUnlimited
+)
+
+// An Altitude is an airspace floor or ceiling.
+type Altitude struct {
+	Text      string  // The original text.
+	Value     float64 // Meters above Reference. Flight levels are converted using the standard atmosphere.
+	Reference Reference
+}
+
+// An Airspace is an airspace.
+type Airspace struct {
+	Class   string
+	Name    string
+	Floor   Altitude
+	Ceiling Altitude
+	Ring    []kml.Coordinate // Closed and counter-clockwise.
+}
+
+// A T parses OpenAir files, approximating arcs and circles on a sphere.
+type T struct {
+	Sphere sphere.T
+	MaxErr float64 // Maximum error of arcs and circles, in meters.
+}
+
+// WGS84 parses OpenAir files using the WGS84 sphere with a maximum error of
+// 10m.
+var WGS84 = T{Sphere: sphere.WGS84, MaxErr: 10}
+
+// A parser holds the state of the airspace being parsed.
+type parser struct {
+	T
+	airspaces []*Airspace
+	airspace  *Airspace
+	center    *kml.Coordinate
+	clockwise bool
+}
+
+// Parse parses all the airspaces in r. Airspaces without any points are
+// omitted. Unsupported records, such as airways and labels, are ignored.
+func (t T) Parse(r io.Reader) ([]*Airspace, error) {
+	p := &parser{T: t}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if err := p.parseLine(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("openair: line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	p.finishAirspace()
+	return p.airspaces, nil
+}
+
+// parseLine parses a single line.
+func (p *parser) parseLine(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '*' {
+		return nil
+	}
+	record, value, _ := strings.Cut(line, " ")
+	value = strings.TrimSpace(value)
+	if record == "AC" {
+		p.finishAirspace()
+		p.airspace = &Airspace{Class: value}
+		p.center = nil
+		p.clockwise = true
+		return nil
+	}
+	if p.airspace == nil {
+		return nil
+	}
+	switch record {
+	case "AN":
+		p.airspace.Name = value
+	case "AL", "AH":
+		altitude, err := ParseAltitude(value)
+		if err != nil {
+			return err
+		}
+		if record == "AL" {
+			p.airspace.Floor = altitude
+		} else {
+			p.airspace.Ceiling = altitude
+		}
+	case "DP":
+		c, err := ParseCoordinate(value)
+		if err != nil {
+			return err
+		}
+		p.airspace.Ring = append(p.airspace.Ring, c)
+	case "V":
+		key, value, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("invalid variable: %q", value)
+		}
+		switch strings.TrimSpace(key) {
+		case "D":
+			switch strings.TrimSpace(value) {
+			case "+":
+				p.clockwise = true
+			case "-":
+				p.clockwise = false
+			default:
+				return fmt.Errorf("invalid direction: %q", value)
+			}
+		case "X":
+			c, err := ParseCoordinate(value)
+			if err != nil {
+				return err
+			}
+			p.center = &c
+		}
+	case "DA":
+		return p.parseDA(value)
+	case "DB":
+		return p.parseDB(value)
+	case "DC":
+		if p.center == nil {
+			return errNoCenter
+		}
+		radius, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid radius: %q", value)
+		}
+		p.airspace.Ring = append(p.airspace.Ring, p.Sphere.Circle(*p.center, radius*nauticalMile, p.MaxErr)...)
+	}
+	return nil
+}
+
+// parseDA parses a DA record, an arc defined by a radius and two bearings.
+func (p *parser) parseDA(value string) error {
+	if p.center == nil {
+		return errNoCenter
+	}
+	fields := strings.Split(value, ",")
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid arc: %q", value)
+	}
+	var values [3]float64
+	for i, field := range fields {
+		var err error
+		if values[i], err = strconv.ParseFloat(strings.TrimSpace(field), 64); err != nil {
+			return fmt.Errorf("invalid arc: %q", value)
+		}
+	}
+	p.appendArc(values[0]*nauticalMile, values[1], values[2])
+	return nil
+}
+
+// parseDB parses a DB record, an arc between two coordinates.
+func (p *parser) parseDB(value string) error {
+	if p.center == nil {
+		return errNoCenter
+	}
+	value1, value2, ok := strings.Cut(value, ",")
+	if !ok {
+		return fmt.Errorf("invalid arc: %q", value)
+	}
+	c1, err := ParseCoordinate(value1)
+	if err != nil {
+		return err
+	}
+	c2, err := ParseCoordinate(value2)
+	if err != nil {
+		return err
+	}
+	radius := p.Sphere.HaversineDistance(*p.center, c1)
+	n := len(p.airspace.Ring)
+	p.appendArc(radius, p.Sphere.InitialBearingTo(*p.center, c1), p.Sphere.InitialBearingTo(*p.center, c2))
+	p.airspace.Ring[n] = c1
+	p.airspace.Ring[len(p.airspace.Ring)-1] = c2
+	return nil
+}
+
+// appendArc appends an arc around the current center in the current
+// direction.
+func (p *parser) appendArc(radius, startBearing, endBearing float64) {
+	if p.clockwise {
+		p.airspace.Ring = append(p.airspace.Ring, p.Sphere.Arc(*p.center, radius, startBearing, endBearing, p.MaxErr)...)
+		return
+	}
+	arc := p.Sphere.Arc(*p.center, radius, endBearing, startBearing, p.MaxErr)
+	slices.Reverse(arc)
+	p.airspace.Ring = append(p.airspace.Ring, arc...)
+}
+
+// finishAirspace adds the current airspace, if any, to the list of
+// airspaces.
+func (p *parser) finishAirspace() {
+	if p.airspace == nil || len(p.airspace.Ring) == 0 {
+		return
+	}
+	p.airspace.Ring = sphere.NormalizeRing(p.airspace.Ring, true)
+	p.airspaces = append(p.airspaces, p.airspace)
+	p.airspace = nil
+}
+
+// ParseAltitude parses an OpenAir altitude, for example SFC, 1500ft AMSL,
+// 2000 ft AGL, FL95, or UNL. Altitudes without a unit are in feet and
+// altitudes without a reference are above mean sea level.
+func ParseAltitude(s string) (Altitude, error) {
+	text := strings.TrimSpace(s)
+	upper := strings.ToUpper(text)
+	switch {
+	case upper == "SFC" || upper == "GND" || upper == "SURFACE":
+		return Altitude{Text: text, Reference: ReferenceSurface}, nil
+	case strings.HasPrefix(upper, "UNL"):
+		return Altitude{Text: text, Value: UnlimitedAlt, Reference: ReferenceUnlimited}, nil
+	}
+	if match := flRx.FindStringSubmatch(upper); match != nil {
+		fl, _ := strconv.Atoi(match[1])
+		return Altitude{Text: text, Value: 100 * float64(fl) * foot, Reference: ReferenceFL}, nil
+	}
+	match := altitudeRx.FindStringSubmatch(upper)
+	if match == nil {
+		return Altitude{}, fmt.Errorf("invalid altitude: %q", s)
+	}
+	value, _ := strconv.ParseFloat(match[1], 64)
+	if match[2] != "M" {
+		value *= foot
+	}
+	reference := ReferenceMSL
+	switch match[3] {
+	case "AGL", "AGND", "ASFC", "GND", "SFC":
+		reference = ReferenceAGL
+		if value == 0 {
+			reference = ReferenceSurface
+		}
+	}
+	return Altitude{Text: text, Value: value, Reference: reference}, nil
+}
+
+// ParseCoordinate parses an OpenAir coordinate, for example
+// 46:30:00 N 008:30:00 E or 46:30.5N 008:30.25E.
+func ParseCoordinate(s string) (kml.Coordinate, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	i := strings.IndexAny(upper, "NS")
+	if i == -1 {
+		return kml.Coordinate{}, fmt.Errorf("invalid coordinate: %q", s)
+	}
+	j := strings.IndexAny(upper[i+1:], "EW")
+	if j == -1 || strings.TrimSpace(upper[i+1+j+1:]) != "" {
+		return kml.Coordinate{}, fmt.Errorf("invalid coordinate: %q", s)
+	}
+	lat, err := parseDMS(upper[:i])
+	if err != nil || lat > 90 {
+		return kml.Coordinate{}, fmt.Errorf("invalid coordinate: %q", s)
+	}
+	lon, err := parseDMS(upper[i+1 : i+1+j])
+	if err != nil || lon > 180 {
+		return kml.Coordinate{}, fmt.Errorf("invalid coordinate: %q", s)
+	}
+	if upper[i] == 'S' {
+		lat = -lat
+	}
+	if upper[i+1+j] == 'W' {
+		lon = -lon
+	}
+	return kml.Coordinate{Lon: lon, Lat: lat}, nil
+}
+
+// parseDMS parses degrees, minutes, and seconds separated by colons. Minutes
+// and seconds are optional and may be fractional.
+func parseDMS(s string) (float64, error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	if len(fields) > 3 {
+		return 0, fmt.Errorf("invalid angle: %q", s)
+	}
+	result := 0.0
+	scale := 1.0
+	for _, field := range fields {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid angle: %q", s)
+		}
+		result += value / scale
+		scale *= 60
+	}
+	return result, nil
+}
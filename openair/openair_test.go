@@ -0,0 +1,221 @@
+package openair_test
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/openair"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+const testOpenAir = `* Test airspace
+AC CTR
+AN Test CTR
+AL SFC
+AH 4500ft AMSL
+V X=46:00:00 N 008:00:00 E
+DC 5
+
+AC R
+AN Test R
+AL 2000 ft AGL
+AH FL95
+DP 46:30:00 N 008:00:00 E
+V D=-
+V X=46:30:00 N 008:30:00 E
+DA 10,270,90
+DP 46:25:00 N 008:30:00 E
+
+AC E
+AN Test E
+AL FL65
+AH UNL
+V X=47:00:00 N 009:00:00 E
+DB 47:10:00 N 009:00:00 E, 47:00:00 N 009:14.6667 E
+DP 46:50:00 N 009:00:00 E
+
+AC D
+AN Empty
+`
+
+func TestParse(t *testing.T) {
+	airspaces, err := openair.WGS84.Parse(strings.NewReader(testOpenAir))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(airspaces))
+
+	ctr := airspaces[0]
+	assert.Equal(t, "CTR", ctr.Class)
+	assert.Equal(t, "Test CTR", ctr.Name)
+	assert.Equal(t, openair.Altitude{Text: "SFC", Reference: openair.ReferenceSurface}, ctr.Floor)
+	assert.Equal(t, "4500ft AMSL", ctr.Ceiling.Text)
+	assertInDelta(t, 4500*0.3048, ctr.Ceiling.Value, 1e-9)
+	assert.Equal(t, openair.ReferenceMSL, ctr.Ceiling.Reference)
+	assert.True(t, sphere.IsCounterClockwise(ctr.Ring))
+	assert.Equal(t, ctr.Ring[0], ctr.Ring[len(ctr.Ring)-1])
+	for _, c := range ctr.Ring {
+		assertInDelta(t, 5*1852, sphere.WGS84.HaversineDistance(kml.Coordinate{Lon: 8, Lat: 46}, c), 1e-6)
+	}
+
+	r := airspaces[1]
+	assert.Equal(t, openair.ReferenceAGL, r.Floor.Reference)
+	assertInDelta(t, 2000*0.3048, r.Floor.Value, 1e-9)
+	assert.Equal(t, openair.ReferenceFL, r.Ceiling.Reference)
+	assertInDelta(t, 9500*0.3048, r.Ceiling.Value, 1e-9)
+	assert.True(t, sphere.IsCounterClockwise(r.Ring))
+	// The counter-clockwise arc from 270 to 90 passes south of the center.
+	minLat, maxLat := math.Inf(1), math.Inf(-1)
+	for _, c := range r.Ring {
+		minLat = min(minLat, c.Lat)
+		maxLat = max(maxLat, c.Lat)
+	}
+	assertInDelta(t, 46.5-10*1852/sphere.WGS84.R*180/math.Pi, minLat, 1e-4)
+	assertInDelta(t, 46.5, maxLat, 1e-6)
+
+	e := airspaces[2]
+	assert.Equal(t, openair.ReferenceUnlimited, e.Ceiling.Reference)
+	assert.Equal(t, openair.UnlimitedAlt, e.Ceiling.Value)
+	assert.True(t, sphere.IsCounterClockwise(e.Ring))
+	assert.True(t, containsCoordinate(e.Ring, kml.Coordinate{Lon: 9, Lat: 47 + 10.0/60}))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		openAir  string
+		expected string
+	}{
+		{
+			name:     "invalid_altitude",
+			openAir:  "AC R\nAL 1000 furlongs\n",
+			expected: `openair: line 2: invalid altitude: "1000 furlongs"`,
+		},
+		{
+			name:     "invalid_coordinate",
+			openAir:  "AC R\nDP 46:00:00 X 008:00:00 E\n",
+			expected: `openair: line 2: invalid coordinate: "46:00:00 X 008:00:00 E"`,
+		},
+		{
+			name:     "circle_without_center",
+			openAir:  "AC R\nDC 5\n",
+			expected: `openair: line 2: arc or circle without center`,
+		},
+		{
+			name:     "invalid_arc",
+			openAir:  "AC R\nV X=46:00:00 N 008:00:00 E\nDA 5,90\n",
+			expected: `openair: line 3: invalid arc: "5,90"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := openair.WGS84.Parse(strings.NewReader(tc.openAir))
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestParseAltitude(t *testing.T) {
+	for _, tc := range []struct {
+		s         string
+		value     float64
+		reference openair.Reference
+	}{
+		{s: "GND", reference: openair.ReferenceSurface},
+		{s: "0 AGL", reference: openair.ReferenceSurface},
+		{s: "1000", value: 304.8, reference: openair.ReferenceMSL},
+		{s: "1000 m", value: 1000, reference: openair.ReferenceMSL},
+		{s: "1500MSL", value: 457.2, reference: openair.ReferenceMSL},
+		{s: "3000 ft GND", value: 914.4, reference: openair.ReferenceAGL},
+		{s: "fl 100", value: 3048, reference: openair.ReferenceFL},
+		{s: "UNLIM", value: openair.UnlimitedAlt, reference: openair.ReferenceUnlimited},
+	} {
+		t.Run(tc.s, func(t *testing.T) {
+			altitude, err := openair.ParseAltitude(tc.s)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.s, altitude.Text)
+			assertInDelta(t, tc.value, altitude.Value, 1e-9)
+			assert.Equal(t, tc.reference, altitude.Reference)
+		})
+	}
+}
+
+func TestParseCoordinate(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		expected kml.Coordinate
+	}{
+		{s: "46:30:00 N 008:30:00 E", expected: kml.Coordinate{Lon: 8.5, Lat: 46.5}},
+		{s: "46:30.5N 008:15.0W", expected: kml.Coordinate{Lon: -8.25, Lat: 46 + 30.5/60}},
+		{s: "33:45:36S 151:12:00E", expected: kml.Coordinate{Lon: 151.2, Lat: -33.76}},
+	} {
+		t.Run(tc.s, func(t *testing.T) {
+			actual, err := openair.ParseCoordinate(tc.s)
+			assert.NoError(t, err)
+			assertInDelta(t, tc.expected.Lon, actual.Lon, 1e-9)
+			assertInDelta(t, tc.expected.Lat, actual.Lat, 1e-9)
+		})
+	}
+}
+
+func TestDocument(t *testing.T) {
+	airspaces, err := openair.WGS84.Parse(strings.NewReader(testOpenAir))
+	assert.NoError(t, err)
+	document := openair.Document(airspaces, kml.Name("Airspace"))
+
+	var styles, folders []string
+	for _, child := range document.Children {
+		switch child := child.(type) {
+		case *kml.StyleElement:
+			styles = append(styles, child.ID)
+		case *kml.FolderElement:
+			folders = append(folders, child.Children[0].(*kml.NameElement).Value) //nolint:forcetypeassert
+		}
+	}
+	assert.Equal(t, []string{"openair-ctr", "openair-e", "openair-r"}, styles)
+	assert.Equal(t, []string{"CTR", "E", "R"}, folders)
+
+	ctrPlacemark, err := xml.Marshal(airspaces[0].Placemark())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(ctrPlacemark), ``+
+		`<Placemark>`+
+		`<name>Test CTR</name>`+
+		`<description>CTR SFC - 4500ft AMSL</description>`+
+		`<styleUrl>#openair-ctr</styleUrl>`+
+		`<Polygon>`+
+		`<extrude>1</extrude>`+
+		`<altitudeMode>absolute</altitudeMode>`,
+	))
+
+	rPlacemark := airspaces[1].Placemark()
+	multiGeometry, ok := rPlacemark.Children[3].(*kml.MultiGeometryElement)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(multiGeometry.Children))
+	assert.Equal(t, kml.Element(kml.AltitudeMode(kml.AltitudeModeAbsolute)), multiGeometry.Children[0].(*kml.PolygonElement).Children[0])         //nolint:forcetypeassert
+	assert.Equal(t, kml.Element(kml.AltitudeMode(kml.AltitudeModeRelativeToGround)), multiGeometry.Children[1].(*kml.PolygonElement).Children[0]) //nolint:forcetypeassert
+
+	ePlacemark := airspaces[2].Placemark()
+	multiGeometry, ok = ePlacemark.Children[3].(*kml.MultiGeometryElement)
+	assert.True(t, ok)
+	assert.Equal(t, 2+len(airspaces[2].Ring)-1, len(multiGeometry.Children))
+}
+
+func assertInDelta(tb testing.TB, expected, actual, delta float64) {
+	tb.Helper()
+	if math.Abs(expected-actual) <= delta {
+		return
+	}
+	tb.Fatalf("Expected %f to be within %f of %f", actual, delta, expected)
+}
+
+// containsCoordinate returns if cs contains a coordinate close to c.
+func containsCoordinate(cs []kml.Coordinate, c kml.Coordinate) bool {
+	for _, c2 := range cs {
+		if math.Abs(c.Lon-c2.Lon) < 1e-9 && math.Abs(c.Lat-c2.Lat) < 1e-9 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+// Package polyline encodes and decodes Google encoded polylines.
+//
+// github.com/twpayne/go-polyline implements the same algorithm on [][]float64
+// latitude, longitude pairs. This package exists so that KML coordinates and
+// LineStrings can be converted without adding a dependency to this module or
+// swapping coordinate order, and the algorithm is small enough that a second
+// implementation is cheaper than the dependency.
+//
+// See https://developers.google.com/maps/documentation/utilities/polylinealgorithm.
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+var errTruncated = errors.New("polyline: truncated")
+
+// A T encodes and decodes polylines with a given precision.
+type T struct {
+	Precision int // Number of decimal places.
+}
+
+var (
+	// Precision5 is the precision used by Google Maps.
+	Precision5 = T{Precision: 5}
+	// Precision6 is the precision used by OSRM and Valhalla.
+	Precision6 = T{Precision: 6}
+)
+
+// Decode decodes the polyline s. Altitudes are zero.
+func (t T) Decode(s string) ([]kml.Coordinate, error) {
+	factor := t.factor()
+	cs := make([]kml.Coordinate, 0, len(s)/4)
+	var lat, lon int64
+	for i := 0; i < len(s); {
+		deltaLat, n, err := decodeValue(s[i:])
+		if err != nil {
+			return nil, fmt.Errorf("polyline: offset %d: %w", i, err)
+		}
+		i += n
+		if i == len(s) {
+			return nil, errTruncated
+		}
+		deltaLon, n, err := decodeValue(s[i:])
+		if err != nil {
+			return nil, fmt.Errorf("polyline: offset %d: %w", i, err)
+		}
+		i += n
+		lat += deltaLat
+		lon += deltaLon
+		cs = append(cs, kml.Coordinate{
+			Lon: float64(lon) / factor,
+			Lat: float64(lat) / factor,
+		})
+	}
+	return cs, nil
+}
+
+// DecodeLineString decodes the polyline s and returns it as a LineString
+// element with the given children.
+func (t T) DecodeLineString(s string, children ...kml.Element) (*kml.LineStringElement, error) {
+	cs, err := t.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return kml.LineString(append(children, kml.Coordinates(cs...))...), nil
+}
+
+// Encode returns the polyline encoding of cs. Altitudes are ignored.
+func (t T) Encode(cs []kml.Coordinate) string {
+	factor := t.factor()
+	var builder strings.Builder
+	builder.Grow(8 * len(cs))
+	var prevLat, prevLon int64
+	for _, c := range cs {
+		lat := int64(math.Round(c.Lat * factor))
+		lon := int64(math.Round(c.Lon * factor))
+		encodeValue(&builder, lat-prevLat)
+		encodeValue(&builder, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+	return builder.String()
+}
+
+// EncodeLineString returns the polyline encoding of the coordinates of
+// lineString.
+func (t T) EncodeLineString(lineString *kml.LineStringElement) (string, error) {
+	for _, child := range lineString.Children {
+		if cs, dim := kml.CoordinatesOf(child); dim != 0 {
+			return t.Encode(cs), nil
+		}
+	}
+	return "", errors.New("polyline: no coordinates")
+}
+
+// factor returns the scale factor for t's precision.
+func (t T) factor() float64 {
+	return math.Pow10(t.Precision)
+}
+
+// decodeValue decodes a single signed value from the start of s and returns
+// it and the number of bytes consumed.
+func decodeValue(s string) (int64, int, error) {
+	var result uint64
+	for i := range len(s) {
+		if i >= 12 {
+			return 0, 0, errors.New("value too long")
+		}
+		b := s[i]
+		if b < 63 || b > 126 {
+			return 0, 0, fmt.Errorf("invalid character %q", b)
+		}
+		chunk := uint64(b - 63)
+		result |= (chunk & 0x1f) << (5 * i)
+		if chunk&0x20 == 0 {
+			value := int64(result >> 1) //nolint:gosec
+			if result&1 != 0 {
+				value = ^value
+			}
+			return value, i + 1, nil
+		}
+	}
+	return 0, 0, errTruncated
+}
+
+// encodeValue appends the encoding of value to builder.
+func encodeValue(builder *strings.Builder, value int64) {
+	u := uint64(value) << 1 //nolint:gosec
+	if value < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		builder.WriteByte(byte(0x20|u&0x1f) + 63)
+		u >>= 5
+	}
+	builder.WriteByte(byte(u) + 63)
+}
@@ -0,0 +1,88 @@
+package polyline_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/polyline"
+)
+
+func TestPolyline(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		t    polyline.T
+		s    string
+		cs   []kml.Coordinate
+	}{
+		{
+			name: "empty",
+			t:    polyline.Precision5,
+			s:    "",
+			cs:   []kml.Coordinate{},
+		},
+		{
+			name: "google_example",
+			t:    polyline.Precision5,
+			s:    "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			cs: []kml.Coordinate{
+				{Lon: -120.2, Lat: 38.5},
+				{Lon: -120.95, Lat: 40.7},
+				{Lon: -126.453, Lat: 43.252},
+			},
+		},
+		{
+			name: "precision6",
+			t:    polyline.Precision6,
+			s:    "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI",
+			cs: []kml.Coordinate{
+				{Lon: -120.2, Lat: 38.5},
+				{Lon: -120.95, Lat: 40.7},
+				{Lon: -126.453, Lat: 43.252},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.s, tc.t.Encode(tc.cs))
+			actual, err := tc.t.Decode(tc.s)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tc.cs), len(actual))
+			for i, c := range actual {
+				assert.True(t, math.Abs(tc.cs[i].Lon-c.Lon) < 1e-9)
+				assert.True(t, math.Abs(tc.cs[i].Lat-c.Lat) < 1e-9)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{
+		"_p~iF",
+		"_p~iF~ps|",
+		"_p~iF ps|U",
+		"~~~~~~~~~~~~~~",
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := polyline.Precision5.Decode(s)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLineString(t *testing.T) {
+	lineString, err := polyline.Precision5.DecodeLineString("_p~iF~ps|U_ulLnnqC_mqNvxq`@", kml.Tessellate(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(lineString.Children))
+	s, err := polyline.Precision5.EncodeLineString(lineString)
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", s)
+
+	s, err = polyline.Precision5.EncodeLineString(kml.LineString(kml.CoordinatesFlat([]float64{-120.2, 38.5, 0, -120.95, 40.7, 0}, 0, 6, 3, 3)))
+	assert.NoError(t, err)
+	assert.Equal(t, "_p~iF~ps|U_ulLnnqC", s)
+
+	_, err = polyline.Precision5.EncodeLineString(kml.LineString())
+	assert.Error(t, err)
+}
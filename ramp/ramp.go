@@ -0,0 +1,142 @@
+// Package ramp colors tracks by value using color ramps.
+package ramp
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// DefaultBuckets is the number of colors in a linear ramp if Ramp.Buckets is
+// zero.
+const DefaultBuckets = 16
+
+// A Ramp maps values to a finite number of colors, called buckets.
+type Ramp struct {
+	Palette kml.Palette // Colors evenly spaced from Min to Max.
+	Min     float64
+	Max     float64
+	Stepped bool    // If true, each palette color is a bucket. Otherwise, colors are interpolated.
+	Buckets int     // Number of buckets of linear ramps. Zero means DefaultBuckets.
+	Width   float64 // Width of lines in styles, or zero for the default.
+}
+
+// Bucket returns the bucket of value, or -1 if value is NaN. Values outside
+// the range from r.Min to r.Max are clamped.
+func (r Ramp) Bucket(value float64) int {
+	if math.IsNaN(value) {
+		return -1
+	}
+	n := r.NumBuckets()
+	f := 0.0
+	if r.Max != r.Min {
+		f = (value - r.Min) / (r.Max - r.Min)
+	}
+	return min(max(int(f*float64(n)), 0), n-1)
+}
+
+// BucketColor returns the color of bucket. The colors of a linear ramp's
+// buckets range from the first to the last palette color.
+func (r Ramp) BucketColor(bucket int) kml.ABGR {
+	if r.Stepped {
+		return r.Palette[bucket]
+	}
+	n := r.NumBuckets()
+	if n == 1 {
+		return r.Palette[0]
+	}
+	return r.Palette.At(float64(bucket) / float64(n-1))
+}
+
+// BucketName returns a name for bucket describing its range of values, for
+// example "10 to 20".
+func (r Ramp) BucketName(bucket int) string {
+	lower, upper := r.BucketRange(bucket)
+	return strconv.FormatFloat(lower, 'g', 4, 64) + " to " + strconv.FormatFloat(upper, 'g', 4, 64)
+}
+
+// BucketRange returns the range of values in bucket.
+func (r Ramp) BucketRange(bucket int) (float64, float64) {
+	n := float64(r.NumBuckets())
+	return r.Min + (r.Max-r.Min)*float64(bucket)/n, r.Min + (r.Max-r.Min)*float64(bucket+1)/n
+}
+
+// Color returns the color of value.
+func (r Ramp) Color(value float64) kml.ABGR {
+	return r.BucketColor(max(r.Bucket(value), 0))
+}
+
+// Folder returns a Folder containing a shared Style for each bucket used and
+// a Placemark for each bucket with a MultiGeometry of the parts of the line
+// cs in that bucket. values contains a value for each coordinate of cs, and
+// each segment's bucket is that of the mean of its endpoints' values.
+// Segments with NaN values are omitted. id is the prefix of the Styles' ids.
+// children are added to each LineString.
+func (r Ramp) Folder(id string, cs []kml.Coordinate, values []float64, children ...kml.Element) *kml.FolderElement {
+	n := min(len(cs), len(values))
+	multiGeometries := make(map[int]*kml.MultiGeometryElement)
+	var buckets []int
+	start := 0
+	for start < n-1 {
+		bucket := r.Bucket((values[start] + values[start+1]) / 2)
+		end := start + 1
+		for end < n-1 && r.Bucket((values[end]+values[end+1])/2) == bucket {
+			end++
+		}
+		if bucket >= 0 {
+			multiGeometry, ok := multiGeometries[bucket]
+			if !ok {
+				multiGeometry = kml.MultiGeometry()
+				multiGeometries[bucket] = multiGeometry
+				buckets = append(buckets, bucket)
+			}
+			lineString := kml.LineString(children...)
+			lineString.Append(kml.Coordinates(cs[start : end+1]...))
+			multiGeometry.Append(lineString)
+		}
+		start = end
+	}
+
+	folder := kml.Folder()
+	for _, bucket := range buckets {
+		folder.Append(r.Style(id, bucket))
+	}
+	for _, bucket := range buckets {
+		folder.Append(
+			kml.Placemark(
+				kml.Name(r.BucketName(bucket)),
+				kml.StyleURL("#"+styleID(id, bucket)),
+				multiGeometries[bucket],
+			),
+		)
+	}
+	return folder
+}
+
+// NumBuckets returns the number of buckets.
+func (r Ramp) NumBuckets() int {
+	switch {
+	case r.Stepped:
+		return len(r.Palette)
+	case r.Buckets > 0:
+		return r.Buckets
+	default:
+		return DefaultBuckets
+	}
+}
+
+// Style returns a shared Style for bucket. id is the prefix of the Style's
+// id.
+func (r Ramp) Style(id string, bucket int) *kml.StyleElement {
+	lineStyle := kml.LineStyle(kml.Color(r.BucketColor(bucket)))
+	if r.Width != 0 {
+		lineStyle.Append(kml.Width(r.Width))
+	}
+	return kml.SharedStyle(styleID(id, bucket), lineStyle)
+}
+
+// styleID returns the id of the shared Style for bucket.
+func styleID(id string, bucket int) string {
+	return id + "-" + strconv.Itoa(bucket)
+}
@@ -0,0 +1,95 @@
+package ramp_test
+
+import (
+	"encoding/xml"
+	"math"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/ramp"
+)
+
+func TestRamp(t *testing.T) {
+	linear := ramp.Ramp{
+		Palette: kml.Viridis,
+		Min:     0,
+		Max:     100,
+		Buckets: 5,
+	}
+	assert.Equal(t, 5, linear.NumBuckets())
+	assert.Equal(t, 0, linear.Bucket(-10))
+	assert.Equal(t, 0, linear.Bucket(19.9))
+	assert.Equal(t, 1, linear.Bucket(20))
+	assert.Equal(t, 4, linear.Bucket(100))
+	assert.Equal(t, 4, linear.Bucket(1000))
+	assert.Equal(t, -1, linear.Bucket(math.NaN()))
+	assert.Equal(t, kml.Viridis[0], linear.Color(0))
+	assert.Equal(t, kml.Viridis[5], linear.Color(50))
+	assert.Equal(t, kml.Viridis[10], linear.Color(100))
+	lower, upper := linear.BucketRange(1)
+	assert.Equal(t, 20.0, lower)
+	assert.Equal(t, 40.0, upper)
+
+	stepped := ramp.Ramp{
+		Palette: kml.Palette{0xff0000ff, 0xff00ff00, 0xffff0000},
+		Min:     -1,
+		Max:     2,
+		Stepped: true,
+	}
+	assert.Equal(t, 3, stepped.NumBuckets())
+	assert.Equal(t, kml.ABGR(0xff0000ff), stepped.Color(-0.5))
+	assert.Equal(t, kml.ABGR(0xff00ff00), stepped.Color(0.5))
+	assert.Equal(t, kml.ABGR(0xffff0000), stepped.Color(1.5))
+
+	interpolated := ramp.Ramp{
+		Palette: kml.Palette{0xff000000, 0xff0080ff},
+		Max:     1,
+		Buckets: 3,
+	}
+	assert.Equal(t, kml.ABGR(0xff004080), interpolated.BucketColor(1))
+}
+
+func TestFolder(t *testing.T) {
+	r := ramp.Ramp{
+		Palette: kml.Palette{0xff0000ff, 0xffff0000},
+		Min:     0,
+		Max:     2,
+		Stepped: true,
+		Width:   3,
+	}
+	cs := []kml.Coordinate{
+		{Lon: 0, Lat: 0},
+		{Lon: 1, Lat: 0},
+		{Lon: 2, Lat: 0},
+		{Lon: 3, Lat: 0},
+		{Lon: 4, Lat: 0},
+		{Lon: 5, Lat: 0},
+		{Lon: 6, Lat: 0},
+	}
+	values := []float64{0, 0, 0, 2, 2, math.NaN(), 0}
+	folder := r.Folder("speed", cs, values, kml.Tessellate(true))
+	actual, err := xml.Marshal(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, ``+
+		`<Folder>`+
+		`<Style id="speed-0"><LineStyle><color>ff0000ff</color><width>3</width></LineStyle></Style>`+
+		`<Style id="speed-1"><LineStyle><color>ffff0000</color><width>3</width></LineStyle></Style>`+
+		`<Placemark>`+
+		`<name>0 to 1</name>`+
+		`<styleUrl>#speed-0</styleUrl>`+
+		`<MultiGeometry>`+
+		`<LineString><tessellate>1</tessellate><coordinates>0,0 1,0 2,0</coordinates></LineString>`+
+		`</MultiGeometry>`+
+		`</Placemark>`+
+		`<Placemark>`+
+		`<name>1 to 2</name>`+
+		`<styleUrl>#speed-1</styleUrl>`+
+		`<MultiGeometry>`+
+		`<LineString><tessellate>1</tessellate><coordinates>2,0 3,0 4,0</coordinates></LineString>`+
+		`</MultiGeometry>`+
+		`</Placemark>`+
+		`</Folder>`,
+		string(actual))
+}
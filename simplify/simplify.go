@@ -0,0 +1,246 @@
+// Package simplify simplifies lines of coordinates. Tolerances are measured in
+// meters on a sphere rather than in degrees.
+//
+// Lines are simplified in place: the retained coordinates are moved to the
+// start of the input and the length of the simplified line is returned. This
+// avoids copying large tracks and allows the result to be passed directly to
+// kml.Coordinates or kml.CoordinatesFlat.
+package simplify
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+// A T simplifies lines on a sphere.
+type T struct {
+	Sphere sphere.T
+	Alt    bool // Include differences in altitude in distances.
+}
+
+// FAI simplifies lines on the FAI sphere, ignoring altitude.
+var FAI = T{Sphere: sphere.FAI}
+
+// DouglasPeucker simplifies cs in place using the Douglas-Peucker algorithm
+// so that no removed coordinate is further than tolerance from the simplified
+// line. It returns the simplified prefix of cs.
+//
+// See https://en.wikipedia.org/wiki/Ramer%E2%80%93Douglas%E2%80%93Peucker_algorithm.
+func (t T) DouglasPeucker(cs []kml.Coordinate, tolerance float64) []kml.Coordinate {
+	keep := t.douglasPeucker(len(cs), coordinateAt(cs), tolerance)
+	return cs[:compact(keep, func(dst, src int) { cs[dst] = cs[src] })]
+}
+
+// DouglasPeuckerFlat simplifies the flat coordinates in
+// flatCoords[offset:end] with the given stride in place using the
+// Douglas-Peucker algorithm. It returns the new end. If t.Alt is true and
+// stride is greater than two then the third value of each coordinate is used
+// as the altitude.
+func (t T) DouglasPeuckerFlat(flatCoords []float64, offset, end, stride int, tolerance float64) int {
+	keep := t.douglasPeucker((end-offset)/stride, flatCoordinateAt(flatCoords, offset, stride), tolerance)
+	return offset + stride*compact(keep, flatMove(flatCoords, offset, stride))
+}
+
+// VisvalingamWhyatt simplifies cs in place using the Visvalingam-Whyatt
+// algorithm, removing coordinates whose effective area, in square meters, is
+// less than minArea. It returns the simplified prefix of cs.
+//
+// See https://en.wikipedia.org/wiki/Visvalingam%E2%80%93Whyatt_algorithm.
+func (t T) VisvalingamWhyatt(cs []kml.Coordinate, minArea float64) []kml.Coordinate {
+	keep := t.visvalingamWhyatt(len(cs), coordinateAt(cs), minArea)
+	return cs[:compact(keep, func(dst, src int) { cs[dst] = cs[src] })]
+}
+
+// VisvalingamWhyattFlat simplifies the flat coordinates in
+// flatCoords[offset:end] with the given stride in place using the
+// Visvalingam-Whyatt algorithm. It returns the new end. If t.Alt is true and
+// stride is greater than two then the third value of each coordinate is used
+// as the altitude.
+func (t T) VisvalingamWhyattFlat(flatCoords []float64, offset, end, stride int, minArea float64) int {
+	keep := t.visvalingamWhyatt((end-offset)/stride, flatCoordinateAt(flatCoords, offset, stride), minArea)
+	return offset + stride*compact(keep, flatMove(flatCoords, offset, stride))
+}
+
+// distance returns the distance between c1 and c2.
+func (t T) distance(c1, c2 kml.Coordinate) float64 {
+	distance := t.Sphere.HaversineDistance(c1, c2)
+	if t.Alt {
+		return math.Hypot(distance, c2.Alt-c1.Alt)
+	}
+	return distance
+}
+
+// segmentDistance returns the distance from c to the segment from start to
+// end.
+func (t T) segmentDistance(c, start, end kml.Coordinate) float64 {
+	return t.distance(c, t.Sphere.NearestPointOnSegment(c, start, end))
+}
+
+// triangleArea returns the area of the triangle with vertices c1, c2, and c3
+// using Heron's formula.
+func (t T) triangleArea(c1, c2, c3 kml.Coordinate) float64 {
+	a := t.distance(c1, c2)
+	b := t.distance(c2, c3)
+	c := t.distance(c3, c1)
+	s := (a + b + c) / 2
+	return math.Sqrt(max(0, s*(s-a)*(s-b)*(s-c)))
+}
+
+// douglasPeucker returns which of the n coordinates returned by at are
+// retained by the Douglas-Peucker algorithm.
+func (t T) douglasPeucker(n int, at func(int) kml.Coordinate, tolerance float64) []bool {
+	keep := make([]bool, n)
+	if n == 0 {
+		return keep
+	}
+	keep[0] = true
+	keep[n-1] = true
+	type span struct{ start, end int }
+	stack := []span{{0, n - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		start, end := at(s.start), at(s.end)
+		maxDistance, maxIndex := 0.0, -1
+		for i := s.start + 1; i < s.end; i++ {
+			if distance := t.segmentDistance(at(i), start, end); distance > maxDistance {
+				maxDistance, maxIndex = distance, i
+			}
+		}
+		if maxIndex != -1 && maxDistance > tolerance {
+			keep[maxIndex] = true
+			stack = append(stack, span{s.start, maxIndex}, span{maxIndex, s.end})
+		}
+	}
+	return keep
+}
+
+// visvalingamWhyatt returns which of the n coordinates returned by at are
+// retained by the Visvalingam-Whyatt algorithm.
+func (t T) visvalingamWhyatt(n int, at func(int) kml.Coordinate, minArea float64) []bool {
+	keep := make([]bool, n)
+	for i := range keep {
+		keep[i] = true
+	}
+	if n < 3 {
+		return keep
+	}
+	prev := make([]int, n)
+	next := make([]int, n)
+	h := &areaHeap{
+		areas:   make([]float64, n),
+		indexes: make([]int, n),
+	}
+	for i := range n {
+		prev[i] = i - 1
+		next[i] = i + 1
+		h.indexes[i] = -1
+	}
+	for i := 1; i < n-1; i++ {
+		h.areas[i] = t.triangleArea(at(i-1), at(i), at(i+1))
+		h.push(i)
+	}
+	heap.Init(h)
+	maxArea := 0.0
+	for h.Len() > 0 {
+		i := h.elements[0]
+		// Ensure that each point's effective area is at least that of the
+		// previously eliminated point.
+		maxArea = max(maxArea, h.areas[i])
+		if maxArea >= minArea {
+			break
+		}
+		heap.Pop(h)
+		keep[i] = false
+		p, q := prev[i], next[i]
+		next[p] = q
+		prev[q] = p
+		for _, j := range []int{p, q} {
+			if h.indexes[j] < 0 {
+				continue
+			}
+			h.areas[j] = t.triangleArea(at(prev[j]), at(j), at(next[j]))
+			heap.Fix(h, h.indexes[j])
+		}
+	}
+	return keep
+}
+
+// An areaHeap is a min-heap of coordinate indexes ordered by effective area.
+type areaHeap struct {
+	elements []int
+	areas    []float64
+	indexes  []int
+}
+
+func (h *areaHeap) Len() int           { return len(h.elements) }
+func (h *areaHeap) Less(i, j int) bool { return h.areas[h.elements[i]] < h.areas[h.elements[j]] }
+
+func (h *areaHeap) Swap(i, j int) {
+	h.elements[i], h.elements[j] = h.elements[j], h.elements[i]
+	h.indexes[h.elements[i]] = i
+	h.indexes[h.elements[j]] = j
+}
+
+func (h *areaHeap) Push(x any) {
+	h.push(x.(int)) //nolint:forcetypeassert
+}
+
+func (h *areaHeap) Pop() any {
+	n := len(h.elements)
+	element := h.elements[n-1]
+	h.elements = h.elements[:n-1]
+	h.indexes[element] = -1
+	return element
+}
+
+func (h *areaHeap) push(element int) {
+	h.indexes[element] = len(h.elements)
+	h.elements = append(h.elements, element)
+}
+
+// compact moves the retained elements to the start using move and returns the
+// number of retained elements.
+func compact(keep []bool, move func(dst, src int)) int {
+	n := 0
+	for i, k := range keep {
+		if !k {
+			continue
+		}
+		if n != i {
+			move(n, i)
+		}
+		n++
+	}
+	return n
+}
+
+// coordinateAt returns a function that returns the ith element of cs.
+func coordinateAt(cs []kml.Coordinate) func(int) kml.Coordinate {
+	return func(i int) kml.Coordinate {
+		return cs[i]
+	}
+}
+
+// flatCoordinateAt returns a function that returns the ith coordinate of
+// flatCoords.
+func flatCoordinateAt(flatCoords []float64, offset, stride int) func(int) kml.Coordinate {
+	return func(i int) kml.Coordinate {
+		j := offset + i*stride
+		c := kml.Coordinate{Lon: flatCoords[j], Lat: flatCoords[j+1]}
+		if stride > 2 {
+			c.Alt = flatCoords[j+2]
+		}
+		return c
+	}
+}
+
+// flatMove returns a function that moves coordinates within flatCoords.
+func flatMove(flatCoords []float64, offset, stride int) func(int, int) {
+	return func(dst, src int) {
+		copy(flatCoords[offset+dst*stride:offset+(dst+1)*stride], flatCoords[offset+src*stride:offset+(src+1)*stride])
+	}
+}
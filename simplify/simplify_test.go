@@ -0,0 +1,91 @@
+package simplify_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/simplify"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+// zigzag returns a line heading east with a small northward deviation at every
+// other coordinate.
+func zigzag(n int, deviation float64) []kml.Coordinate {
+	cs := make([]kml.Coordinate, n)
+	for i := range cs {
+		cs[i] = sphere.FAI.Offset(kml.Coordinate{Lon: 8, Lat: 46}, 100*float64(i), 90)
+		if i%2 == 1 {
+			cs[i] = sphere.FAI.Offset(cs[i], deviation, 0)
+		}
+	}
+	return cs
+}
+
+// vShape returns a line heading east that dips 50m south at its fifth
+// coordinate, with small northward deviations at every other coordinate.
+func vShape() []kml.Coordinate {
+	cs := zigzag(11, 1)
+	for i := range cs {
+		dip := 50 * float64(i) / 4
+		if i > 4 {
+			dip = 50 * float64(10-i) / 6
+		}
+		cs[i] = sphere.FAI.Offset(cs[i], dip, 180)
+	}
+	return cs
+}
+
+func TestDouglasPeucker(t *testing.T) {
+	cs := zigzag(11, 5)
+	first, last := cs[0], cs[len(cs)-1]
+	assert.Equal(t, 11, len(simplify.FAI.DouglasPeucker(zigzag(11, 5), 1)))
+	actual := simplify.FAI.DouglasPeucker(cs, 10)
+	assert.Equal(t, []kml.Coordinate{first, last}, actual)
+
+	cs = []kml.Coordinate{{Lon: 8, Lat: 46}, {Lon: 8.001, Lat: 46, Alt: 50}, {Lon: 8.002, Lat: 46}}
+	assert.Equal(t, 2, len(simplify.FAI.DouglasPeucker(cs, 10)))
+	cs = []kml.Coordinate{{Lon: 8, Lat: 46}, {Lon: 8.001, Lat: 46, Alt: 50}, {Lon: 8.002, Lat: 46}}
+	altT := simplify.T{Sphere: sphere.FAI, Alt: true}
+	assert.Equal(t, 3, len(altT.DouglasPeucker(cs, 10)))
+
+	assert.Equal(t, 0, len(simplify.FAI.DouglasPeucker(nil, 10)))
+}
+
+func TestDouglasPeuckerFlat(t *testing.T) {
+	cs := vShape()
+	flatCoords := []float64{-1}
+	for _, c := range cs {
+		flatCoords = append(flatCoords, c.Lon, c.Lat, c.Alt)
+	}
+	end := simplify.FAI.DouglasPeuckerFlat(flatCoords, 1, len(flatCoords), 3, 10)
+	assert.Equal(t, []float64{
+		-1,
+		cs[0].Lon, cs[0].Lat, cs[0].Alt,
+		cs[4].Lon, cs[4].Lat, cs[4].Alt,
+		cs[10].Lon, cs[10].Lat, cs[10].Alt,
+	}, flatCoords[:end])
+}
+
+func TestVisvalingamWhyatt(t *testing.T) {
+	cs := vShape()
+	expected := []kml.Coordinate{cs[0], cs[4], cs[10]}
+	assert.Equal(t, expected, simplify.FAI.VisvalingamWhyatt(cs, 1000))
+	assert.Equal(t, 11, len(simplify.FAI.VisvalingamWhyatt(zigzag(11, 5), 100)))
+	assert.Equal(t, 2, len(simplify.FAI.VisvalingamWhyatt(zigzag(2, 5), 1e9)))
+}
+
+func TestVisvalingamWhyattFlat(t *testing.T) {
+	cs := vShape()
+	flatCoords := make([]float64, 0, 2*len(cs))
+	for _, c := range cs {
+		flatCoords = append(flatCoords, c.Lon, c.Lat)
+	}
+	end := simplify.FAI.VisvalingamWhyattFlat(flatCoords, 0, len(flatCoords), 2, 1000)
+	assert.Equal(t, []float64{
+		cs[0].Lon, cs[0].Lat,
+		cs[4].Lon, cs[4].Lat,
+		cs[10].Lon, cs[10].Lat,
+	}, flatCoords[:end])
+}
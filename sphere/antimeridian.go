@@ -0,0 +1,321 @@
+package sphere
+
+import (
+	"math"
+	"slices"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// CrossesAntimeridian returns if any segment of cs crosses the antimeridian,
+// assuming that each segment is the shortest path between its endpoints.
+// Segments that only touch the antimeridian do not cross it.
+func CrossesAntimeridian(cs []kml.Coordinate) bool {
+	for i := 1; i < len(cs); i++ {
+		lon := NormalizeLon(cs[i-1].Lon)
+		deltaLon := NormalizeLon(cs[i].Lon - cs[i-1].Lon)
+		if lon == -180 && deltaLon < 0 {
+			lon = 180
+		}
+		if lon+deltaLon < -180-epsilon || 180+epsilon < lon+deltaLon {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize returns a copy of cs with all longitudes normalized to the range
+// [-180, 180), except that a point on the antimeridian has longitude 180 if
+// the nearest previous point that is not on the antimeridian, or the nearest
+// following one for leading points, is east of 0. Lines that touch the
+// antimeridian from the east are therefore not folded to the west.
+func Normalize(cs []kml.Coordinate) []kml.Coordinate {
+	result := make([]kml.Coordinate, len(cs))
+	for i, c := range cs {
+		result[i] = kml.Coordinate{Lon: NormalizeLon(c.Lon), Lat: c.Lat, Alt: c.Alt}
+	}
+	east := false
+	if i := slices.IndexFunc(result, func(c kml.Coordinate) bool { return c.Lon != -180 }); i >= 0 {
+		east = result[i].Lon > 0
+	}
+	for i := range result {
+		switch {
+		case result[i].Lon != -180:
+			east = result[i].Lon > 0
+		case east:
+			result[i].Lon = 180
+		}
+	}
+	return result
+}
+
+// NormalizeLon returns lon normalized to the range [-180, 180).
+func NormalizeLon(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
+
+// SplitLineString splits the line string cs at every point where it crosses
+// the antimeridian and returns the parts with normalized longitudes. Each
+// segment is assumed to be the shortest path between its endpoints. It
+// returns no parts if cs is empty.
+func SplitLineString(cs []kml.Coordinate) [][]kml.Coordinate {
+	if len(cs) == 0 {
+		return nil
+	}
+	if !CrossesAntimeridian(cs) {
+		return [][]kml.Coordinate{Normalize(cs)}
+	}
+	unwrapped := unwrap(cs)
+	var parts [][]kml.Coordinate
+	frame := lonFrame(unwrapped[0].Lon)
+	part := []kml.Coordinate{shiftLon(unwrapped[0], frame)}
+	for i := 1; i < len(unwrapped); i++ {
+		c1, c2 := unwrapped[i-1], unwrapped[i]
+		if nextFrame := lonFrame(c2.Lon); nextFrame != frame {
+			boundary := -180 + 360*float64(max(frame, nextFrame))
+			c := interpolateLon(c1, c2, boundary)
+			part = appendDistinct(part, shiftLon(c, frame))
+			if len(part) > 1 {
+				parts = append(parts, part)
+			}
+			part = []kml.Coordinate{shiftLon(c, nextFrame)}
+			frame = nextFrame
+		}
+		part = appendDistinct(part, shiftLon(c2, frame))
+	}
+	if len(part) > 1 {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// SplitLineStringGeometry returns a MultiGeometry containing a LineString for
+// each part of cs split at the antimeridian. children are added to each
+// LineString.
+func SplitLineStringGeometry(cs []kml.Coordinate, children ...kml.Element) *kml.MultiGeometryElement {
+	multiGeometry := kml.MultiGeometry()
+	for _, part := range SplitLineString(cs) {
+		multiGeometry.Append(kml.LineString(slices.Concat(children, []kml.Element{kml.Coordinates(part...)})...))
+	}
+	return multiGeometry
+}
+
+// SplitPolygonGeometry returns a MultiGeometry containing a Polygon for each
+// part of the outer ring outer split at the antimeridian. The inner rings
+// inners are split in the same way and each of their parts is added as an
+// inner boundary to the part of outer that contains it. children are added to
+// each Polygon.
+func SplitPolygonGeometry(outer []kml.Coordinate, inners [][]kml.Coordinate, children ...kml.Element) *kml.MultiGeometryElement {
+	outerParts := SplitRing(outer)
+	innerParts := make([][][]kml.Coordinate, len(outerParts))
+	for _, inner := range inners {
+		for _, innerPart := range SplitRing(inner) {
+			// Find the outer part containing a point of the inner part that is
+			// not on the antimeridian, where the parts meet.
+			i := slices.IndexFunc(innerPart, func(c kml.Coordinate) bool {
+				return c.Lon != -180 && c.Lon != 180
+			})
+			if i < 0 {
+				continue
+			}
+			if j := slices.IndexFunc(outerParts, func(outerPart []kml.Coordinate) bool {
+				return planarRingContains(outerPart, innerPart[i])
+			}); j >= 0 {
+				innerParts[j] = append(innerParts[j], innerPart)
+			}
+		}
+	}
+
+	multiGeometry := kml.MultiGeometry()
+	for i, outerPart := range outerParts {
+		polygonChildren := slices.Concat(children, []kml.Element{
+			kml.OuterBoundaryIs(
+				kml.LinearRing(
+					kml.Coordinates(outerPart...),
+				),
+			),
+		})
+		for _, innerPart := range innerParts[i] {
+			polygonChildren = append(polygonChildren,
+				kml.InnerBoundaryIs(
+					kml.LinearRing(
+						kml.Coordinates(innerPart...),
+					),
+				),
+			)
+		}
+		multiGeometry.Append(kml.Polygon(polygonChildren...))
+	}
+	return multiGeometry
+}
+
+// SplitRing splits the ring cs into closed rings that do not cross the
+// antimeridian, with normalized longitudes. Rings that enclose a pole are
+// extended to include it. Each segment is assumed to be the shortest path
+// between its endpoints.
+func SplitRing(cs []kml.Coordinate) [][]kml.Coordinate {
+	if len(cs) == 0 {
+		return nil
+	}
+	if first, last := cs[0], cs[len(cs)-1]; NormalizeLon(first.Lon) != NormalizeLon(last.Lon) || first.Lat != last.Lat {
+		cs = append(cs[:len(cs):len(cs)], first)
+	}
+	unwrapped := unwrap(cs)
+
+	// If the ring does not return to its starting longitude then it encloses a
+	// pole, so extend it to the pole.
+	first, last := unwrapped[0], unwrapped[len(unwrapped)-1]
+	if math.Abs(last.Lon-first.Lon) > 180 {
+		sumLat := 0.0
+		for _, c := range unwrapped {
+			sumLat += c.Lat
+		}
+		poleLat := 90.0
+		if sumLat < 0 {
+			poleLat = -90
+		}
+		unwrapped = append(unwrapped,
+			kml.Coordinate{Lon: last.Lon, Lat: poleLat, Alt: last.Alt},
+			kml.Coordinate{Lon: first.Lon, Lat: poleLat, Alt: first.Alt},
+			first,
+		)
+	} else {
+		unwrapped[len(unwrapped)-1] = first
+	}
+
+	minFrame, maxFrame := lonFrame(unwrapped[0].Lon), lonFrame(unwrapped[0].Lon)
+	for _, c := range unwrapped[1:] {
+		minFrame = min(minFrame, lonFrame(c.Lon))
+		maxFrame = max(maxFrame, lonFrame(c.Lon))
+	}
+
+	var rings [][]kml.Coordinate
+	for frame := minFrame; frame <= maxFrame; frame++ {
+		west := -180 + 360*float64(frame)
+		east := west + 360
+		clipped := clipLon(unwrapped, west, func(lon float64) bool { return lon >= west })
+		clipped = clipLon(clipped, east, func(lon float64) bool { return lon <= east })
+		if len(clipped) < 3 {
+			continue
+		}
+		ring := make([]kml.Coordinate, 0, len(clipped)+1)
+		for _, c := range clipped {
+			ring = appendDistinct(ring, shiftLon(c, frame))
+		}
+		if ring[0] != ring[len(ring)-1] {
+			ring = append(ring, ring[0])
+		}
+		if len(ring) < 4 || isMeridian(ring) {
+			continue
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// SplitCircle returns the closed rings that approximate a circle of the given
+// radius centered on center with a maximum error of maxErr, split at the
+// antimeridian.
+func (t T) SplitCircle(center kml.Coordinate, radius, maxErr float64) [][]kml.Coordinate {
+	return SplitRing(t.Circle(center, radius, maxErr))
+}
+
+// appendDistinct appends c to cs if it is not equal to the last element of cs.
+func appendDistinct(cs []kml.Coordinate, c kml.Coordinate) []kml.Coordinate {
+	if len(cs) > 0 && cs[len(cs)-1] == c {
+		return cs
+	}
+	return append(cs, c)
+}
+
+// clipLon clips the closed ring cs against the meridian at lon using the
+// Sutherland-Hodgman algorithm, keeping the points for which inside returns
+// true. The result is not closed.
+func clipLon(cs []kml.Coordinate, lon float64, inside func(float64) bool) []kml.Coordinate {
+	n := len(cs)
+	if n > 1 && cs[0] == cs[n-1] {
+		n--
+	}
+	var result []kml.Coordinate
+	for i := range n {
+		c1, c2 := cs[(i+n-1)%n], cs[i]
+		switch inside1, inside2 := inside(c1.Lon), inside(c2.Lon); {
+		case inside1 && inside2:
+			result = append(result, c2)
+		case inside1 && !inside2:
+			result = append(result, interpolateLon(c1, c2, lon))
+		case !inside1 && inside2:
+			result = append(result, interpolateLon(c1, c2, lon), c2)
+		}
+	}
+	return result
+}
+
+// interpolateLon returns the point on the straight line between c1 and c2
+// with longitude lon.
+func interpolateLon(c1, c2 kml.Coordinate, lon float64) kml.Coordinate {
+	f := (lon - c1.Lon) / (c2.Lon - c1.Lon)
+	return kml.Coordinate{
+		Lon: lon,
+		Lat: c1.Lat + f*(c2.Lat-c1.Lat),
+		Alt: c1.Alt + f*(c2.Alt-c1.Alt),
+	}
+}
+
+// isMeridian returns if all points in cs have the same longitude.
+func isMeridian(cs []kml.Coordinate) bool {
+	for _, c := range cs[1:] {
+		if c.Lon != cs[0].Lon {
+			return false
+		}
+	}
+	return true
+}
+
+// lonFrame returns the index of the 360 degree wide frame containing lon,
+// where frame zero is [-180, 180).
+func lonFrame(lon float64) int {
+	return int(math.Floor((lon + 180) / 360))
+}
+
+// planarRingContains returns if c is inside the ring cs, treating longitude
+// and latitude as planar coordinates.
+func planarRingContains(cs []kml.Coordinate, c kml.Coordinate) bool {
+	contains := false
+	for i, j := 0, len(cs)-1; i < len(cs); j, i = i, i+1 {
+		c1, c2 := cs[j], cs[i]
+		if (c1.Lat > c.Lat) != (c2.Lat > c.Lat) && c.Lon < c1.Lon+(c.Lat-c1.Lat)*(c2.Lon-c1.Lon)/(c2.Lat-c1.Lat) {
+			contains = !contains
+		}
+	}
+	return contains
+}
+
+// shiftLon returns c shifted from frame to frame zero.
+func shiftLon(c kml.Coordinate, frame int) kml.Coordinate {
+	c.Lon -= 360 * float64(frame)
+	return c
+}
+
+// unwrap returns a copy of cs with longitudes adjusted so that no consecutive
+// points differ in longitude by more than 180 degrees. The first longitude is
+// normalized.
+func unwrap(cs []kml.Coordinate) []kml.Coordinate {
+	if len(cs) == 0 {
+		return nil
+	}
+	result := make([]kml.Coordinate, len(cs))
+	result[0] = kml.Coordinate{Lon: NormalizeLon(cs[0].Lon), Lat: cs[0].Lat, Alt: cs[0].Alt}
+	for i := 1; i < len(cs); i++ {
+		result[i] = kml.Coordinate{
+			Lon: result[i-1].Lon + NormalizeLon(cs[i].Lon-cs[i-1].Lon),
+			Lat: cs[i].Lat,
+			Alt: cs[i].Alt,
+		}
+	}
+	return result
+}
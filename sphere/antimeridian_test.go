@@ -0,0 +1,172 @@
+package sphere_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+func TestNormalizeLon(t *testing.T) {
+	for i, tc := range []struct {
+		lon      float64
+		expected float64
+	}{
+		{lon: 0, expected: 0},
+		{lon: 179, expected: 179},
+		{lon: 180, expected: -180},
+		{lon: 181, expected: -179},
+		{lon: -181, expected: 179},
+		{lon: 540, expected: -180},
+		{lon: -720, expected: 0},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, sphere.NormalizeLon(tc.lon))
+		})
+	}
+}
+
+func TestSplitLineString(t *testing.T) {
+	for i, tc := range []struct {
+		cs       []kml.Coordinate
+		expected [][]kml.Coordinate
+	}{
+		{
+			cs: []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 10, Lat: 10}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 0, Lat: 0}, {Lon: 10, Lat: 10}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: 170, Lat: 0, Alt: 100}, {Lon: -170, Lat: 10, Alt: 200}, {Lon: -160, Lat: 10}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 170, Lat: 0, Alt: 100}, {Lon: 180, Lat: 5, Alt: 150}},
+				{{Lon: -180, Lat: 5, Alt: 150}, {Lon: -170, Lat: 10, Alt: 200}, {Lon: -160, Lat: 10}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: -170, Lat: 0}, {Lon: 170, Lat: 10}, {Lon: -170, Lat: 20}},
+			expected: [][]kml.Coordinate{
+				{{Lon: -170, Lat: 0}, {Lon: -180, Lat: 5}},
+				{{Lon: 180, Lat: 5}, {Lon: 170, Lat: 10}, {Lon: 180, Lat: 15}},
+				{{Lon: -180, Lat: 15}, {Lon: -170, Lat: 20}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: 170, Lat: 0}, {Lon: 180, Lat: 0}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 170, Lat: 0}, {Lon: 180, Lat: 0}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: -180, Lat: 0}, {Lon: 180, Lat: 10}, {Lon: 170, Lat: 10}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 180, Lat: 0}, {Lon: 180, Lat: 10}, {Lon: 170, Lat: 10}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: -170, Lat: 0}, {Lon: 180, Lat: 0}},
+			expected: [][]kml.Coordinate{
+				{{Lon: -170, Lat: 0}, {Lon: -180, Lat: 0}},
+			},
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, sphere.SplitLineString(tc.cs))
+		})
+	}
+}
+
+func TestSplitRing(t *testing.T) {
+	for i, tc := range []struct {
+		cs       []kml.Coordinate
+		expected [][]kml.Coordinate
+	}{
+		{
+			cs: []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 10, Lat: 0}, {Lon: 10, Lat: 10}, {Lon: 360, Lat: 10}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 0, Lat: 0}, {Lon: 10, Lat: 0}, {Lon: 10, Lat: 10}, {Lon: 0, Lat: 10}, {Lon: 0, Lat: 0}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: 170, Lat: 0}, {Lon: -170, Lat: 0}, {Lon: -170, Lat: 10}, {Lon: 170, Lat: 10}, {Lon: 170, Lat: 0}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 170, Lat: 0}, {Lon: 180, Lat: 0}, {Lon: 180, Lat: 10}, {Lon: 170, Lat: 10}, {Lon: 170, Lat: 0}},
+				{{Lon: -180, Lat: 0}, {Lon: -170, Lat: 0}, {Lon: -170, Lat: 10}, {Lon: -180, Lat: 10}, {Lon: -180, Lat: 0}},
+			},
+		},
+		{
+			cs: []kml.Coordinate{{Lon: 0, Lat: 80}, {Lon: 120, Lat: 80}, {Lon: -120, Lat: 80}, {Lon: 0, Lat: 80}},
+			expected: [][]kml.Coordinate{
+				{{Lon: 0, Lat: 80}, {Lon: 120, Lat: 80}, {Lon: 180, Lat: 80}, {Lon: 180, Lat: 90}, {Lon: 0, Lat: 90}, {Lon: 0, Lat: 80}},
+				{{Lon: -180, Lat: 80}, {Lon: -120, Lat: 80}, {Lon: 0, Lat: 80}, {Lon: 0, Lat: 90}, {Lon: -180, Lat: 90}, {Lon: -180, Lat: 80}},
+			},
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, sphere.SplitRing(tc.cs))
+		})
+	}
+}
+
+func TestSplitCircle(t *testing.T) {
+	center := kml.Coordinate{Lon: 179.99, Lat: 10}
+	assert.True(t, sphere.CrossesAntimeridian(sphere.Normalize(sphere.WGS84.Circle(center, 5000, 1))))
+	rings := sphere.WGS84.SplitCircle(center, 5000, 1)
+	assert.Equal(t, 2, len(rings))
+	area := 0.0
+	for _, ring := range rings {
+		assert.False(t, sphere.CrossesAntimeridian(ring))
+		assert.Equal(t, ring[0], ring[len(ring)-1])
+		area += sphere.WGS84.Area(ring)
+	}
+	assertInDelta(t, sphere.WGS84.Area(sphere.WGS84.Circle(center, 5000, 1)), area, 1e3)
+
+	multiGeometry := sphere.SplitPolygonGeometry(sphere.WGS84.Circle(center, 5000, 1), nil, kml.AltitudeMode(kml.AltitudeModeClampToGround))
+	assert.Equal(t, 2, len(multiGeometry.Children))
+}
+
+func TestSplitLineStringGeometry(t *testing.T) {
+	children := make([]kml.Element, 1, 4)
+	children[0] = kml.Tessellate(true)
+	multiGeometry := sphere.SplitLineStringGeometry([]kml.Coordinate{
+		{Lon: 170, Lat: 0}, {Lon: -170, Lat: 10}, {Lon: 170, Lat: 20}, {Lon: -170, Lat: 30},
+	}, children...)
+	assert.Equal(t, kml.MultiGeometry(
+		kml.LineString(kml.Tessellate(true), kml.Coordinates(kml.Coordinate{Lon: 170, Lat: 0}, kml.Coordinate{Lon: 180, Lat: 5})),
+		kml.LineString(kml.Tessellate(true), kml.Coordinates(kml.Coordinate{Lon: -180, Lat: 5}, kml.Coordinate{Lon: -170, Lat: 10}, kml.Coordinate{Lon: -180, Lat: 15})),
+		kml.LineString(kml.Tessellate(true), kml.Coordinates(kml.Coordinate{Lon: 180, Lat: 15}, kml.Coordinate{Lon: 170, Lat: 20}, kml.Coordinate{Lon: 180, Lat: 25})),
+		kml.LineString(kml.Tessellate(true), kml.Coordinates(kml.Coordinate{Lon: -180, Lat: 25}, kml.Coordinate{Lon: -170, Lat: 30})),
+	), multiGeometry)
+
+	assert.Equal(t, 0, len(sphere.SplitLineString(nil)))
+	assert.Equal(t, 0, len(sphere.SplitLineStringGeometry(nil).Children))
+}
+
+func TestSplitPolygonGeometry(t *testing.T) {
+	children := make([]kml.Element, 1, 4)
+	children[0] = kml.AltitudeMode(kml.AltitudeModeClampToGround)
+	outer := []kml.Coordinate{{Lon: 170, Lat: -10}, {Lon: -170, Lat: -10}, {Lon: -170, Lat: 10}, {Lon: 170, Lat: 10}, {Lon: 170, Lat: -10}}
+	crossingHole := []kml.Coordinate{{Lon: 175, Lat: -5}, {Lon: 175, Lat: 5}, {Lon: -175, Lat: 5}, {Lon: -175, Lat: -5}, {Lon: 175, Lat: -5}}
+	eastHole := []kml.Coordinate{{Lon: 172, Lat: 6}, {Lon: 172, Lat: 8}, {Lon: 174, Lat: 8}, {Lon: 172, Lat: 6}}
+	multiGeometry := sphere.SplitPolygonGeometry(outer, [][]kml.Coordinate{crossingHole, eastHole}, children...)
+	assert.Equal(t, 2, len(multiGeometry.Children))
+
+	var innerBoundaries []int
+	for _, child := range multiGeometry.Children {
+		polygon := child.(*kml.PolygonElement) //nolint:forcetypeassert
+		assert.Equal(t, children[0], polygon.Children[0])
+		outerBoundary := polygon.Children[1].(*kml.OuterBoundaryIsElement)                                          //nolint:forcetypeassert
+		outerCoordinates := outerBoundary.Children[0].(*kml.LinearRingElement).Children[0].(kml.CoordinatesElement) //nolint:forcetypeassert
+		assert.False(t, sphere.CrossesAntimeridian(outerCoordinates))
+		innerBoundaries = append(innerBoundaries, len(polygon.Children)-2)
+		for _, innerBoundary := range polygon.Children[2:] {
+			innerCoordinates := innerBoundary.(*kml.InnerBoundaryIsElement).Children[0].(*kml.LinearRingElement).Children[0].(kml.CoordinatesElement) //nolint:forcetypeassert
+			assert.Equal(t, outerCoordinates[0].Lon > 0, innerCoordinates[0].Lon > 0)
+		}
+	}
+	assert.Equal(t, []int{2, 1}, innerBoundaries)
+}
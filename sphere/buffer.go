@@ -0,0 +1,107 @@
+package sphere
+
+import (
+	"math"
+	"slices"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// minMiterCos is the minimum cosine of half the turn angle for which a miter
+// join is used on the inside of a turn.
+const minMiterCos = 0.1
+
+// BufferPath returns a closed counter-clockwise ring that approximates the
+// corridor of the given distance around the path cs, with round caps and
+// round joins, with a maximum error of maxErr. Paths that turn sharply
+// relative to distance may produce rings that intersect themselves on the
+// inside of the turn.
+func (t T) BufferPath(cs []kml.Coordinate, distance, maxErr float64) []kml.Coordinate {
+	path := make([]kml.Coordinate, 0, len(cs))
+	for _, c := range cs {
+		if len(path) > 0 && path[len(path)-1].Lon == c.Lon && path[len(path)-1].Lat == c.Lat {
+			continue
+		}
+		path = append(path, c)
+	}
+	switch len(path) {
+	case 0:
+		return nil
+	case 1:
+		return t.BufferPoint(path[0], distance, maxErr)
+	}
+	ring := t.bufferSide(path, distance, maxErr)
+	slices.Reverse(path)
+	ring = append(ring, t.bufferSide(path, distance, maxErr)...)
+	ring = append(ring, ring[0])
+	slices.Reverse(ring)
+	return ring
+}
+
+// BufferPoint returns a closed counter-clockwise ring that approximates the
+// circle of the given distance around c with a maximum error of maxErr.
+func (t T) BufferPoint(c kml.Coordinate, distance, maxErr float64) []kml.Coordinate {
+	ring := t.Circle(c, distance, maxErr)
+	slices.Reverse(ring)
+	return ring
+}
+
+// BufferPolygon returns a closed counter-clockwise ring that approximates the
+// outer ring of the polygon ring offset outwards by distance, with round
+// joins, with a maximum error of maxErr. ring may be in either winding order.
+// Polygons with concave features that are small relative to distance may
+// produce rings that intersect themselves.
+func (t T) BufferPolygon(ring []kml.Coordinate, distance, maxErr float64) []kml.Coordinate {
+	ring = NormalizeRing(ring, false)
+	if len(ring) < 4 {
+		if len(ring) == 0 {
+			return nil
+		}
+		return t.BufferPath(ring, distance, maxErr)
+	}
+	ring = ring[:len(ring)-1]
+	n := len(ring)
+	var result []kml.Coordinate
+	for i := range n {
+		result = t.appendJoin(result, ring[(i+n-1)%n], ring[i], ring[(i+1)%n], distance, maxErr)
+	}
+	result = append(result, result[0])
+	slices.Reverse(result)
+	return result
+}
+
+// appendJoin appends the points on the left of the join at v between the
+// segment from p to v and the segment from v to q.
+func (t T) appendJoin(cs []kml.Coordinate, p, v, q kml.Coordinate, distance, maxErr float64) []kml.Coordinate {
+	bearingIn := t.FinalBearingTo(p, v)
+	bearingOut := t.InitialBearingTo(v, q)
+	turn := math.Remainder(bearingOut-bearingIn, 360)
+	switch {
+	case math.Abs(turn) < epsilon:
+		return append(cs, t.Offset(v, distance, bearingIn-90))
+	case turn > 0:
+		// The left side is on the outside of the turn, so join with an arc.
+		return append(cs, t.Arc(v, distance, bearingIn-90, bearingOut-90, maxErr)...)
+	default:
+		// The left side is on the inside of the turn, so join with a miter if
+		// the turn is not too sharp.
+		cosHalfTurn := math.Cos(turn / 2 * radians)
+		if cosHalfTurn < minMiterCos {
+			return append(cs, t.Offset(v, distance, bearingIn-90), t.Offset(v, distance, bearingOut-90))
+		}
+		return append(cs, t.Offset(v, distance/cosHalfTurn, bearingIn-90+turn/2))
+	}
+}
+
+// bufferSide returns the points on the left of the path cs, including the cap
+// at the end of cs. The first point on the left of cs is omitted because it is
+// the last point of the cap at the start of cs.
+func (t T) bufferSide(cs []kml.Coordinate, distance, maxErr float64) []kml.Coordinate {
+	n := len(cs)
+	var result []kml.Coordinate
+	for i := 1; i < n-1; i++ {
+		result = t.appendJoin(result, cs[i-1], cs[i], cs[i+1], distance, maxErr)
+	}
+	bearing := t.FinalBearingTo(cs[n-2], cs[n-1])
+	return append(result, t.Arc(cs[n-1], distance, bearing-90, bearing+90, maxErr)...)
+}
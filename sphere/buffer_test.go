@@ -0,0 +1,100 @@
+package sphere_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+func TestBufferPath(t *testing.T) {
+	origin := kml.Coordinate{Lon: 8, Lat: 46}
+	for i, tc := range []struct {
+		path         []kml.Coordinate
+		distance     float64
+		maxErr       float64
+		expectedArea float64
+	}{
+		{
+			path:         []kml.Coordinate{origin},
+			distance:     400,
+			maxErr:       1,
+			expectedArea: math.Pi * 400 * 400,
+		},
+		{
+			path:         []kml.Coordinate{origin, origin, sphere.FAI.Offset(origin, 10000, 45)},
+			distance:     400,
+			maxErr:       1,
+			expectedArea: 2*400*10000 + math.Pi*400*400,
+		},
+		{
+			path: []kml.Coordinate{
+				origin,
+				sphere.FAI.Offset(origin, 5000, 0),
+				sphere.FAI.Offset(sphere.FAI.Offset(origin, 5000, 0), 5000, 90),
+			},
+			distance:     400,
+			maxErr:       1,
+			expectedArea: 2*400*10000 + math.Pi*400*400,
+		},
+		{
+			path: []kml.Coordinate{
+				origin,
+				sphere.FAI.Offset(origin, 5000, 0),
+				sphere.FAI.Offset(sphere.FAI.Offset(origin, 5000, 0), 5000, 270),
+			},
+			distance:     400,
+			maxErr:       1,
+			expectedArea: 2*400*10000 + math.Pi*400*400,
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			ring := sphere.FAI.BufferPath(tc.path, tc.distance, tc.maxErr)
+			assert.Equal(t, ring[0], ring[len(ring)-1])
+			assert.True(t, sphere.IsCounterClockwise(ring))
+			assert.Equal(t, 0, len(sphere.RingIntersections(ring)))
+			for _, c := range ring {
+				assertInDelta(t, tc.distance, sphere.FAI.DistanceToPath(c, tc.path), 1e-3)
+			}
+			assertInDelta(t, tc.expectedArea, sphere.FAI.Area(ring), 0.01*tc.expectedArea)
+		})
+	}
+}
+
+func TestBufferPolygon(t *testing.T) {
+	origin := kml.Coordinate{Lon: 8, Lat: 46}
+	square := []kml.Coordinate{
+		origin,
+		sphere.FAI.Offset(origin, 1000, 90),
+		sphere.FAI.Offset(sphere.FAI.Offset(origin, 1000, 90), 1000, 0),
+		sphere.FAI.Offset(origin, 1000, 0),
+		origin,
+	}
+	ring := sphere.FAI.BufferPolygon(square, 100, 1)
+	assert.Equal(t, ring[0], ring[len(ring)-1])
+	assert.True(t, sphere.IsCounterClockwise(ring))
+	for _, c := range ring {
+		assert.False(t, sphere.FAI.Contains(c, square))
+		assertInDelta(t, 100, sphere.FAI.DistanceToPath(c, square), 1)
+	}
+	assertInDelta(t, 1000*1000+4*1000*100+math.Pi*100*100, sphere.FAI.Area(ring), 1000)
+
+	// A concave polygon.
+	l := []kml.Coordinate{
+		origin,
+		sphere.FAI.Offset(origin, 2000, 90),
+		sphere.FAI.Offset(sphere.FAI.Offset(origin, 2000, 90), 1000, 0),
+		sphere.FAI.Offset(sphere.FAI.Offset(origin, 1000, 90), 1000, 0),
+		sphere.FAI.Offset(sphere.FAI.Offset(origin, 1000, 90), 2000, 0),
+		sphere.FAI.Offset(origin, 2000, 0),
+	}
+	ring = sphere.FAI.BufferPolygon(l, 100, 1)
+	for _, c := range ring {
+		assert.False(t, sphere.FAI.Contains(c, l))
+		assertInDelta(t, 100, sphere.FAI.DistanceToPath(c, append(l, l[0])), 1)
+	}
+}
@@ -0,0 +1,143 @@
+package sphere
+
+import (
+	"math"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+const maxDensifyDepth = 32
+
+// DensifyGreatCircle returns a copy of cs with intermediate points inserted so
+// that each segment follows the great circle between consecutive points. If
+// maxErr is positive then segments are subdivided until the distance between
+// the great circle and a straight line in longitude and latitude is at most
+// maxErr. If maxLength is positive then no segment is longer than maxLength.
+// Altitude is interpolated linearly.
+func (t T) DensifyGreatCircle(cs []kml.Coordinate, maxErr, maxLength float64) []kml.Coordinate {
+	return t.densify(cs, maxErr, maxLength, t.HaversineDistance, t.IntermediatePoint)
+}
+
+// DensifyRhumbLine returns a copy of cs with intermediate points inserted so
+// that each segment follows the rhumb line between consecutive points. If
+// maxErr is positive then segments are subdivided until the distance between
+// the rhumb line and a straight line in longitude and latitude is at most
+// maxErr. If maxLength is positive then no segment is longer than maxLength.
+// Altitude is interpolated linearly.
+func (t T) DensifyRhumbLine(cs []kml.Coordinate, maxErr, maxLength float64) []kml.Coordinate {
+	return t.densify(cs, maxErr, maxLength, t.RhumbDistance, t.rhumbIntermediatePoint)
+}
+
+// RhumbBearingTo returns the constant bearing of the rhumb line from c1 to c2.
+// Altitude is ignored.
+func (t T) RhumbBearingTo(c1, c2 kml.Coordinate) float64 {
+	deltaPsi := projectedLatitudeDelta(c1.Lat*radians, c2.Lat*radians)
+	deltaLon := wrapRadians((c2.Lon - c1.Lon) * radians)
+	return math.Atan2(deltaLon, deltaPsi) * degrees
+}
+
+// RhumbDistance returns the distance along the rhumb line between c1 and c2.
+// Altitude is ignored.
+func (t T) RhumbDistance(c1, c2 kml.Coordinate) float64 {
+	lat1 := c1.Lat * radians
+	lat2 := c2.Lat * radians
+	deltaLat := lat2 - lat1
+	deltaPsi := projectedLatitudeDelta(lat1, lat2)
+	q := math.Cos(lat1)
+	if math.Abs(deltaPsi) > epsilon {
+		q = deltaLat / deltaPsi
+	}
+	deltaLon := wrapRadians((c2.Lon - c1.Lon) * radians)
+	return math.Hypot(deltaLat, q*deltaLon) * t.R
+}
+
+// RhumbOffset returns the coordinate at distance from origin along the rhumb
+// line with bearing. The longitude of the result is normalized to
+// [-180, 180).
+func (t T) RhumbOffset(origin kml.Coordinate, distance, bearing float64) kml.Coordinate {
+	delta := distance / t.R
+	lat1 := origin.Lat * radians
+	deltaLat := delta * math.Cos(bearing*radians)
+	lat2 := lat1 + deltaLat
+	switch {
+	case lat2 > math.Pi/2:
+		lat2 = math.Pi - lat2
+	case lat2 < -math.Pi/2:
+		lat2 = -math.Pi - lat2
+	}
+	deltaPsi := projectedLatitudeDelta(lat1, lat2)
+	q := math.Cos(lat1)
+	if math.Abs(deltaPsi) > epsilon {
+		q = deltaLat / deltaPsi
+	}
+	deltaLon := delta * math.Sin(bearing*radians) / q
+	return kml.Coordinate{
+		Lon: NormalizeLon(origin.Lon + deltaLon*degrees),
+		Lat: lat2 * degrees,
+		Alt: origin.Alt,
+	}
+}
+
+// bisect appends the points needed to draw the curve from c1 to c2 to cs,
+// excluding c1 and including c2. The longitude of c2 is unwrapped relative to
+// c1 when calculating the linear midpoint, so segments that cross the
+// antimeridian are compared with the short straight line between their
+// endpoints.
+func (t T) bisect(cs []kml.Coordinate, c1, c2 kml.Coordinate, maxErr float64, intermediatePoint func(kml.Coordinate, kml.Coordinate, float64) kml.Coordinate, depth int) []kml.Coordinate {
+	if maxErr <= 0 || depth == 0 {
+		return append(cs, c2)
+	}
+	midpoint := intermediatePoint(c1, c2, 0.5)
+	lon2 := c1.Lon + math.Remainder(c2.Lon-c1.Lon, 360)
+	linearMidpoint := kml.Coordinate{Lon: (c1.Lon + lon2) / 2, Lat: (c1.Lat + c2.Lat) / 2}
+	if t.HaversineDistance(midpoint, linearMidpoint) <= maxErr {
+		return append(cs, c2)
+	}
+	cs = t.bisect(cs, c1, midpoint, maxErr, intermediatePoint, depth-1)
+	return t.bisect(cs, midpoint, c2, maxErr, intermediatePoint, depth-1)
+}
+
+// densify returns a copy of cs with intermediate points inserted along the
+// curves defined by distance and intermediatePoint.
+func (t T) densify(cs []kml.Coordinate, maxErr, maxLength float64, distance func(kml.Coordinate, kml.Coordinate) float64, intermediatePoint func(kml.Coordinate, kml.Coordinate, float64) kml.Coordinate) []kml.Coordinate {
+	if len(cs) == 0 {
+		return nil
+	}
+	result := []kml.Coordinate{cs[0]}
+	for i := 1; i < len(cs); i++ {
+		c1, c2 := cs[i-1], cs[i]
+		n := 1
+		if maxLength > 0 {
+			n = max(1, int(math.Ceil(distance(c1, c2)/maxLength)))
+		}
+		prev := c1
+		for j := 1; j <= n; j++ {
+			next := c2
+			if j < n {
+				next = intermediatePoint(c1, c2, float64(j)/float64(n))
+			}
+			result = t.bisect(result, prev, next, maxErr, intermediatePoint, maxDensifyDepth)
+			prev = next
+		}
+	}
+	return result
+}
+
+// rhumbIntermediatePoint returns the point at fraction f along the rhumb line
+// from c1 to c2. Altitude is interpolated linearly.
+func (t T) rhumbIntermediatePoint(c1, c2 kml.Coordinate, f float64) kml.Coordinate {
+	c := t.RhumbOffset(c1, f*t.RhumbDistance(c1, c2), t.RhumbBearingTo(c1, c2))
+	c.Alt = c1.Alt + f*(c2.Alt-c1.Alt)
+	return c
+}
+
+// projectedLatitudeDelta returns the difference in Mercator projected latitude
+// between lat1 and lat2, both in radians.
+func projectedLatitudeDelta(lat1, lat2 float64) float64 {
+	return math.Log(math.Tan(math.Pi/4+lat2/2) / math.Tan(math.Pi/4+lat1/2))
+}
+
+// wrapRadians wraps x, in radians, into the range [-pi, pi].
+func wrapRadians(x float64) float64 {
+	return math.Remainder(x, 2*math.Pi)
+}
@@ -0,0 +1,80 @@
+package sphere_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+func TestDensifyGreatCircle(t *testing.T) {
+	london := kml.Coordinate{Lon: -0.1275, Lat: 51.507222, Alt: 0}
+	newYork := kml.Coordinate{Lon: -74.006, Lat: 40.7128, Alt: 1000}
+
+	assert.Equal(t, []kml.Coordinate{london, newYork}, sphere.WGS84.DensifyGreatCircle([]kml.Coordinate{london, newYork}, 0, 0))
+
+	cs := sphere.WGS84.DensifyGreatCircle([]kml.Coordinate{london, newYork}, 0, 100000)
+	distance := sphere.WGS84.HaversineDistance(london, newYork)
+	assert.Equal(t, 57, len(cs))
+	assert.Equal(t, london, cs[0])
+	assert.Equal(t, newYork, cs[len(cs)-1])
+	for i := 1; i < len(cs); i++ {
+		assertInDelta(t, distance/56, sphere.WGS84.HaversineDistance(cs[i-1], cs[i]), 1e-6)
+		assertInDelta(t, 0, sphere.WGS84.CrossTrackDistance(cs[i], london, newYork), 1e-6)
+		assert.True(t, cs[i-1].Alt < cs[i].Alt)
+	}
+
+	cs = sphere.WGS84.DensifyGreatCircle([]kml.Coordinate{london, newYork}, 1000, 0)
+	assert.True(t, len(cs) > 2)
+	for i := 1; i < len(cs); i++ {
+		midpoint := sphere.WGS84.Midpoint(cs[i-1], cs[i])
+		linearMidpoint := kml.Coordinate{Lon: (cs[i-1].Lon + cs[i].Lon) / 2, Lat: (cs[i-1].Lat + cs[i].Lat) / 2}
+		assert.True(t, sphere.WGS84.HaversineDistance(midpoint, linearMidpoint) <= 1000)
+	}
+
+	antimeridian := []kml.Coordinate{{Lon: 179, Lat: 0}, {Lon: -179, Lat: 0}}
+	assert.Equal(t, antimeridian, sphere.WGS84.DensifyGreatCircle(antimeridian, 1000, 0))
+	cs = sphere.WGS84.DensifyGreatCircle([]kml.Coordinate{{Lon: 170, Lat: 50}, {Lon: -170, Lat: 50}}, 1000, 0)
+	assert.True(t, len(cs) > 2)
+	assert.True(t, len(cs) < 64)
+}
+
+func TestDensifyRhumbLine(t *testing.T) {
+	c1 := kml.Coordinate{Lon: 0, Lat: 0}
+	c2 := kml.Coordinate{Lon: 0, Lat: 60}
+	cs := sphere.WGS84.DensifyRhumbLine([]kml.Coordinate{c1, c2}, 1, 0)
+	assert.Equal(t, []kml.Coordinate{c1, c2}, cs)
+
+	c3 := kml.Coordinate{Lon: 90, Lat: 60}
+	cs = sphere.WGS84.DensifyRhumbLine([]kml.Coordinate{c1, c3}, 0, 1000000)
+	bearing := sphere.WGS84.RhumbBearingTo(c1, c3)
+	for i := 1; i < len(cs)-1; i++ {
+		assertInDelta(t, bearing, sphere.WGS84.RhumbBearingTo(cs[i], c3), 1e-9)
+	}
+
+	c4 := kml.Coordinate{Lon: 90, Lat: 0}
+	cs = sphere.WGS84.DensifyRhumbLine([]kml.Coordinate{c1, c4}, 1, 0)
+	assert.Equal(t, []kml.Coordinate{c1, c4}, cs)
+}
+
+func TestRhumb(t *testing.T) {
+	c1 := kml.Coordinate{Lon: -5.714722, Lat: 50.066389}
+	c2 := kml.Coordinate{Lon: -3.07, Lat: 58.643889}
+	assertInDelta(t, 968.9e3, sphere.FAI.RhumbDistance(c1, c2), 100)
+	assertInDelta(t, 10.140692, sphere.FAI.RhumbBearingTo(c1, c2), 1e-4)
+	c := sphere.FAI.RhumbOffset(c1, sphere.FAI.RhumbDistance(c1, c2), sphere.FAI.RhumbBearingTo(c1, c2))
+	assertInDelta(t, c2.Lon, c.Lon, 1e-9)
+	assertInDelta(t, c2.Lat, c.Lat, 1e-9)
+
+	c = sphere.FAI.RhumbOffset(kml.Coordinate{Lon: 179, Lat: 0}, sphere.FAI.RhumbDistance(kml.Coordinate{Lon: 179, Lat: 0}, kml.Coordinate{Lon: -179, Lat: 0}), 90)
+	assertInDelta(t, -179, c.Lon, 1e-9)
+	assertInDelta(t, 0, c.Lat, 1e-9)
+
+	cs := sphere.FAI.DensifyRhumbLine([]kml.Coordinate{{Lon: 179, Lat: 10}, {Lon: -179, Lat: 20}}, 0, 100e3)
+	assert.True(t, len(cs) > 2)
+	for _, c := range cs {
+		assert.True(t, c.Lon >= -180 && c.Lon < 180)
+	}
+}
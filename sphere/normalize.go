@@ -0,0 +1,226 @@
+package sphere
+
+import (
+	"math"
+	"slices"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// A SelfIntersection is a point where a ring of a polygon intersects itself or
+// another ring of the same polygon.
+type SelfIntersection struct {
+	Polygon    *kml.PolygonElement
+	Coordinate kml.Coordinate
+}
+
+// IsCounterClockwise returns if the ring cs is counter-clockwise when viewed
+// from above. Altitude is ignored.
+func IsCounterClockwise(cs []kml.Coordinate) bool {
+	return ringExcess(cs) < 0
+}
+
+// NormalizePolygons normalizes the rings of every Polygon in the tree rooted
+// at element in place, and returns all self-intersections found. Outer rings
+// are made counter-clockwise and inner rings are made clockwise, each ring is
+// closed, and consecutive duplicate coordinates are removed. Coordinates
+// elements are only replaced if they change, and are replaced with elements of
+// the same type and layout. Finding self-intersections takes time proportional
+// to the square of the number of coordinates in each polygon.
+func NormalizePolygons(element kml.Element) []SelfIntersection {
+	var selfIntersections []SelfIntersection
+	_ = kml.Walk(element, func(element kml.Element) error {
+		polygon, ok := element.(*kml.PolygonElement)
+		if !ok {
+			return nil
+		}
+		var rings [][]kml.Coordinate
+		for _, child := range polygon.Children {
+			switch child := child.(type) {
+			case *kml.OuterBoundaryIsElement:
+				rings = append(rings, normalizeBoundary(child.Children, true)...)
+			case *kml.InnerBoundaryIsElement:
+				rings = append(rings, normalizeBoundary(child.Children, false)...)
+			}
+		}
+		for _, c := range RingIntersections(rings...) {
+			selfIntersections = append(selfIntersections, SelfIntersection{
+				Polygon:    polygon,
+				Coordinate: c,
+			})
+		}
+		return kml.SkipChildren
+	})
+	return selfIntersections
+}
+
+// NormalizeRing returns a copy of the ring cs that is closed, has no
+// consecutive duplicate coordinates, and is counter-clockwise if ccw is true or
+// clockwise otherwise.
+func NormalizeRing(cs []kml.Coordinate, ccw bool) []kml.Coordinate {
+	indices := normalizeRingIndices(cs, ccw)
+	result := make([]kml.Coordinate, 0, len(indices))
+	for _, i := range indices {
+		result = append(result, cs[i])
+	}
+	return result
+}
+
+// RingIntersections returns the points where the great circle edges of rings
+// intersect, either with themselves or with each other. Adjacent edges of the
+// same ring are not considered to intersect at their shared vertex.
+func RingIntersections(rings ...[]kml.Coordinate) []kml.Coordinate {
+	var intersections []kml.Coordinate
+	for i, ring1 := range rings {
+		n1 := len(ring1) - 1
+		for j := i; j < len(rings); j++ {
+			ring2 := rings[j]
+			n2 := len(ring2) - 1
+			for k := range n1 {
+				start := 0
+				if i == j {
+					start = k + 2
+				}
+				for l := start; l < n2; l++ {
+					if i == j && k == 0 && l == n1-1 && ring1[0] == ring1[n1] {
+						continue
+					}
+					if c, ok := arcIntersection(ring1[k], ring1[k+1], ring2[l], ring2[l+1]); ok {
+						intersections = append(intersections, c)
+					}
+				}
+			}
+		}
+	}
+	return intersections
+}
+
+// normalizeBoundary normalizes the rings in the LinearRing children of an
+// outerBoundaryIs or innerBoundaryIs element in place and returns the
+// normalized rings.
+func normalizeBoundary(children []kml.Element, ccw bool) [][]kml.Coordinate {
+	var rings [][]kml.Coordinate
+	for _, child := range children {
+		linearRing, ok := child.(*kml.LinearRingElement)
+		if !ok {
+			continue
+		}
+		for i, child := range linearRing.Children {
+			cs, dim := kml.CoordinatesOf(child)
+			if dim == 0 {
+				continue
+			}
+			indices := normalizeRingIndices(cs, ccw)
+			ring := make([]kml.Coordinate, 0, len(indices))
+			for _, j := range indices {
+				ring = append(ring, cs[j])
+			}
+			if !slices.Equal(ring, cs) {
+				linearRing.Children[i] = selectCoordinates(child, indices)
+			}
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+// arcIntersection returns the intersection of the great circle arc from a1 to
+// a2 with the great circle arc from b1 to b2, if any.
+func arcIntersection(a1, a2, b1, b2 kml.Coordinate) (kml.Coordinate, bool) {
+	p1, p2 := toVector(a1), toVector(a2)
+	q1, q2 := toVector(b1), toVector(b2)
+	n1 := cross(p1, p2)
+	n2 := cross(q1, q2)
+	l := cross(n1, n2)
+	norm := math.Sqrt(dot(l, l))
+	if norm < epsilon {
+		return kml.Coordinate{}, false
+	}
+	for _, sign := range []float64{1, -1} {
+		x := [3]float64{sign * l[0] / norm, sign * l[1] / norm, sign * l[2] / norm}
+		if onArc(x, p1, p2, n1) && onArc(x, q1, q2, n2) {
+			return kml.Coordinate{
+				Lon: math.Atan2(x[1], x[0]) * degrees,
+				Lat: math.Asin(clamp(x[2], -1, 1)) * degrees,
+			}, true
+		}
+	}
+	return kml.Coordinate{}, false
+}
+
+// normalizeRingIndices returns the indices into the ring cs of the
+// coordinates of the ring returned by NormalizeRing.
+func normalizeRingIndices(cs []kml.Coordinate, ccw bool) []int {
+	indices := make([]int, 0, len(cs)+1)
+	ring := make([]kml.Coordinate, 0, len(cs)+1)
+	for i, c := range cs {
+		if len(ring) > 0 && ring[len(ring)-1] == c {
+			continue
+		}
+		indices = append(indices, i)
+		ring = append(ring, c)
+	}
+	if len(ring) == 0 {
+		return indices
+	}
+	if ring[0] != ring[len(ring)-1] {
+		indices = append(indices, indices[0])
+		ring = append(ring, ring[0])
+	}
+	if IsCounterClockwise(ring) != ccw {
+		slices.Reverse(indices)
+	}
+	return indices
+}
+
+// selectCoordinates returns a new coordinates element of the same type and
+// layout as element containing the coordinates of element at indices.
+func selectCoordinates(element kml.Element, indices []int) kml.Element { //nolint:ireturn
+	switch element := element.(type) {
+	case *kml.CoordinatesFlatElement:
+		flatCoords := make([]float64, 0, len(indices)*element.Stride)
+		for _, i := range indices {
+			offset := element.Offset + i*element.Stride
+			flatCoords = append(flatCoords, element.FlatCoords[offset:offset+element.Stride]...)
+		}
+		return kml.CoordinatesFlat(flatCoords, 0, len(flatCoords), element.Stride, element.Dim)
+	case kml.CoordinatesSliceElement:
+		result := make(kml.CoordinatesSliceElement, 0, len(indices))
+		for _, i := range indices {
+			result = append(result, element[i])
+		}
+		return result
+	default:
+		cs, _ := kml.CoordinatesOf(element)
+		result := make(kml.CoordinatesElement, 0, len(indices))
+		for _, i := range indices {
+			result = append(result, cs[i])
+		}
+		return result
+	}
+}
+
+// onArc returns if the unit vector x lies on the great circle arc from a to b
+// with normal n.
+func onArc(x, a, b, n [3]float64) bool {
+	return dot(cross(a, x), n) >= -epsilon && dot(cross(x, b), n) >= -epsilon
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// toVector returns the unit vector corresponding to c.
+func toVector(c kml.Coordinate) [3]float64 {
+	sinLat, cosLat := math.Sincos(c.Lat * radians)
+	sinLon, cosLon := math.Sincos(c.Lon * radians)
+	return [3]float64{cosLat * cosLon, cosLat * sinLon, sinLat}
+}
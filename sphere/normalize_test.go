@@ -0,0 +1,71 @@
+package sphere_test
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+func TestIsCounterClockwise(t *testing.T) {
+	ccw := []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 1, Lat: 0}, {Lon: 1, Lat: 1}, {Lon: 0, Lat: 1}, {Lon: 0, Lat: 0}}
+	assert.True(t, sphere.IsCounterClockwise(ccw))
+	cw := []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 0, Lat: -1}, {Lon: -1, Lat: -1}, {Lon: -1, Lat: 0}}
+	assert.False(t, sphere.IsCounterClockwise(cw))
+	assert.False(t, sphere.IsCounterClockwise(sphere.FAI.Circle(kml.Coordinate{Lon: 179, Lat: -45}, 1000, 1)))
+}
+
+func TestNormalizeRing(t *testing.T) {
+	cs := []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 0, Lat: 1}, {Lon: 0, Lat: 1}, {Lon: 1, Lat: 1}, {Lon: 1, Lat: 0}}
+	assert.Equal(t, []kml.Coordinate{
+		{Lon: 0, Lat: 0}, {Lon: 1, Lat: 0}, {Lon: 1, Lat: 1}, {Lon: 0, Lat: 1}, {Lon: 0, Lat: 0},
+	}, sphere.NormalizeRing(cs, true))
+	assert.Equal(t, []kml.Coordinate{
+		{Lon: 0, Lat: 0}, {Lon: 0, Lat: 1}, {Lon: 1, Lat: 1}, {Lon: 1, Lat: 0}, {Lon: 0, Lat: 0},
+	}, sphere.NormalizeRing(cs, false))
+}
+
+func TestRingIntersections(t *testing.T) {
+	bowtie := []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 1, Lat: 1}, {Lon: 1, Lat: 0}, {Lon: 0, Lat: 1}, {Lon: 0, Lat: 0}}
+	intersections := sphere.RingIntersections(bowtie)
+	assert.Equal(t, 1, len(intersections))
+	assertInDelta(t, 0.5, intersections[0].Lon, 1e-9)
+	assertInDelta(t, 0.5, intersections[0].Lat, 1e-4)
+
+	square := []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 1, Lat: 0}, {Lon: 1, Lat: 1}, {Lon: 0, Lat: 1}, {Lon: 0, Lat: 0}}
+	assert.Equal(t, 0, len(sphere.RingIntersections(square)))
+
+	hole := []kml.Coordinate{{Lon: 0.5, Lat: 0.5}, {Lon: 0.5, Lat: 1.5}, {Lon: 0.75, Lat: 0.5}, {Lon: 0.5, Lat: 0.5}}
+	assert.Equal(t, 2, len(sphere.RingIntersections(square, hole)))
+}
+
+func TestNormalizePolygons(t *testing.T) {
+	outer := kml.CoordinatesSlice([]float64{0, 0}, []float64{0, 1}, []float64{1, 1}, []float64{1, 0})
+	inner := kml.Coordinates(kml.Coordinate{Lon: 0.25, Lat: 0.25}, kml.Coordinate{Lon: 0.5, Lat: 0.25}, kml.Coordinate{Lon: 0.5, Lat: 0.5}, kml.Coordinate{Lon: 0.25, Lat: 0.25})
+	polygon := kml.Polygon(
+		kml.OuterBoundaryIs(kml.LinearRing(outer)),
+		kml.InnerBoundaryIs(kml.LinearRing(inner)),
+	)
+	flat := kml.Polygon(
+		kml.OuterBoundaryIs(kml.LinearRing(kml.CoordinatesFlat([]float64{-1, 2, 0, 0, 0, 3, 0, 1, 0, 3, 1, 0, 0, 3, -1, 2}, 2, 14, 4, 3))),
+	)
+	bowtie := kml.Polygon(
+		kml.OuterBoundaryIs(kml.LinearRing(kml.CoordinatesFlat([]float64{0, 0, 1, 1, 1, 0, 0, 1, 0, 0}, 0, 10, 2, 2))),
+	)
+	selfIntersections := sphere.NormalizePolygons(kml.Document(kml.Placemark(kml.MultiGeometry(polygon, flat, bowtie))))
+
+	assert.Equal(t, kml.Element(kml.CoordinatesSlice(
+		[]float64{0, 0}, []float64{1, 0}, []float64{1, 1}, []float64{0, 1}, []float64{0, 0},
+	)), polygon.Children[0].(*kml.OuterBoundaryIsElement).Children[0].(*kml.LinearRingElement).Children[0])
+	assert.Equal(t, kml.Element(kml.CoordinatesFlat(
+		[]float64{0, 0, 0, 3, 1, 0, 0, 3, 0, 1, 0, 3, 0, 0, 0, 3}, 0, 16, 4, 3,
+	)), flat.Children[0].(*kml.OuterBoundaryIsElement).Children[0].(*kml.LinearRingElement).Children[0])
+	assert.Equal(t, kml.Element(kml.Coordinates(
+		kml.Coordinate{Lon: 0.25, Lat: 0.25}, kml.Coordinate{Lon: 0.5, Lat: 0.5}, kml.Coordinate{Lon: 0.5, Lat: 0.25}, kml.Coordinate{Lon: 0.25, Lat: 0.25},
+	)), polygon.Children[1].(*kml.InnerBoundaryIsElement).Children[0].(*kml.LinearRingElement).Children[0])
+
+	assert.Equal(t, 1, len(selfIntersections))
+	assert.Equal(t, bowtie, selfIntersections[0].Polygon)
+}
@@ -0,0 +1,63 @@
+package sphere
+
+import (
+	"math"
+	"slices"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// Annulus returns the outer and inner rings of an annulus centered on center
+// with the given radii and a maximum error of maxErr. The outer ring is
+// counter-clockwise and the inner ring is clockwise, so they can be used
+// directly in an OuterBoundaryIs and an InnerBoundaryIs.
+func (t T) Annulus(center kml.Coordinate, outerRadius, innerRadius, maxErr float64) ([]kml.Coordinate, []kml.Coordinate) {
+	outer := t.Circle(center, outerRadius, maxErr)
+	slices.Reverse(outer)
+	inner := t.Circle(center, innerRadius, maxErr)
+	return outer, inner
+}
+
+// Arc returns an array of kml.Coordinates that approximate an arc of the given
+// radius centered on center, running clockwise from startBearing to
+// endBearing, with a maximum error of maxErr. If startBearing and endBearing
+// are equal then the arc is a full circle.
+func (t T) Arc(center kml.Coordinate, radius, startBearing, endBearing, maxErr float64) []kml.Coordinate {
+	sweep := math.Mod(endBearing-startBearing, 360)
+	if sweep <= 0 {
+		sweep += 360
+	}
+	n := max(1, int(math.Ceil(float64(CircleVertices(radius, maxErr))*sweep/360)))
+	cs := make([]kml.Coordinate, n+1)
+	for i := range n + 1 {
+		cs[i] = t.Offset(center, radius, startBearing+sweep*float64(i)/float64(n))
+	}
+	return cs
+}
+
+// Ellipse returns an array of kml.Coordinates that approximate an ellipse
+// centered on center with the given semi-major and semi-minor axes, with its
+// major axis in direction rotation, and with a maximum error of maxErr.
+func (t T) Ellipse(center kml.Coordinate, semiMajorAxis, semiMinorAxis, rotation, maxErr float64) []kml.Coordinate {
+	numVertices := CircleVertices(semiMajorAxis, maxErr)
+	cs := make([]kml.Coordinate, numVertices+1)
+	for i := range numVertices {
+		theta := 2 * math.Pi * float64(i) / float64(numVertices)
+		sinTheta, cosTheta := math.Sincos(theta)
+		radius := semiMajorAxis * semiMinorAxis / math.Hypot(semiMinorAxis*cosTheta, semiMajorAxis*sinTheta)
+		cs[i] = t.Offset(center, radius, rotation+theta*degrees)
+	}
+	cs[numVertices] = cs[0]
+	return cs
+}
+
+// Sector returns a closed ring of kml.Coordinates that approximates a sector
+// of a circle of the given radius centered on center, running clockwise from
+// startBearing to endBearing, with a maximum error of maxErr.
+func (t T) Sector(center kml.Coordinate, radius, startBearing, endBearing, maxErr float64) []kml.Coordinate {
+	arc := t.Arc(center, radius, startBearing, endBearing, maxErr)
+	cs := make([]kml.Coordinate, 0, len(arc)+2)
+	cs = append(cs, center)
+	cs = append(cs, arc...)
+	return append(cs, center)
+}
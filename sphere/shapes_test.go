@@ -0,0 +1,69 @@
+package sphere_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/sphere"
+)
+
+func TestArc(t *testing.T) {
+	center := kml.Coordinate{Lon: 13.631333, Lat: 46.438500, Alt: 100}
+	arc := sphere.WGS84.Arc(center, 50, 0, 90, 1)
+	assert.Equal(t, 4, len(arc))
+	assertInDelta(t, 0, sphere.WGS84.InitialBearingTo(center, arc[0]), 1e-9)
+	assertInDelta(t, 90, sphere.WGS84.InitialBearingTo(center, arc[len(arc)-1]), 1e-6)
+	for _, c := range arc {
+		assertInDelta(t, 50, sphere.WGS84.HaversineDistance(center, c), 1e-9)
+		assert.Equal(t, center.Alt, c.Alt)
+	}
+
+	arc = sphere.WGS84.Arc(center, 50, 350, 10, 1)
+	assert.Equal(t, 2, len(arc))
+	assertInDelta(t, -10, sphere.WGS84.InitialBearingTo(center, arc[0]), 1e-9)
+	assertInDelta(t, 10, sphere.WGS84.InitialBearingTo(center, arc[1]), 1e-6)
+
+	circle := sphere.WGS84.Circle(center, 50, 1)
+	arc = sphere.WGS84.Arc(center, 50, 0, 0, 1)
+	assert.Equal(t, len(circle), len(arc))
+	for i := range circle {
+		assertInDelta(t, circle[i].Lon, arc[i].Lon, 1e-12)
+		assertInDelta(t, circle[i].Lat, arc[i].Lat, 1e-12)
+	}
+}
+
+func TestSector(t *testing.T) {
+	center := kml.Coordinate{Lon: 8, Lat: 46}
+	sector := sphere.FAI.Sector(center, 1000, 45, 135, 1)
+	assert.Equal(t, center, sector[0])
+	assert.Equal(t, center, sector[len(sector)-1])
+	assertInDelta(t, math.Pi*1000*1000/4, sphere.FAI.Area(sector), 1e4)
+}
+
+func TestEllipse(t *testing.T) {
+	center := kml.Coordinate{Lon: 8, Lat: 46}
+	ellipse := sphere.FAI.Ellipse(center, 2000, 1000, 30, 1)
+	assert.Equal(t, ellipse[0], ellipse[len(ellipse)-1])
+	assertInDelta(t, 2000, sphere.FAI.HaversineDistance(center, ellipse[0]), 1e-6)
+	assertInDelta(t, 30, sphere.FAI.InitialBearingTo(center, ellipse[0]), 1e-6)
+	assertInDelta(t, math.Pi*2000*1000, sphere.FAI.Area(ellipse), 2000*1000*0.01)
+	for _, c := range ellipse {
+		distance := sphere.FAI.HaversineDistance(center, c)
+		assert.True(t, 1000-1e-6 <= distance && distance <= 2000+1e-6)
+	}
+}
+
+func TestAnnulus(t *testing.T) {
+	center := kml.Coordinate{Lon: 8, Lat: 46}
+	outer, inner := sphere.FAI.Annulus(center, 2000, 1000, 1)
+	assertInDelta(t, 2000, sphere.FAI.HaversineDistance(center, outer[0]), 1e-9)
+	assertInDelta(t, 1000, sphere.FAI.HaversineDistance(center, inner[0]), 1e-9)
+	assertInDelta(t, math.Pi*(2000*2000-1000*1000), sphere.FAI.Area(outer, inner), 1e4)
+	// The outer ring is counter-clockwise.
+	assert.True(t, sphere.FAI.InitialBearingTo(center, outer[1]) < 0)
+	// The inner ring is clockwise.
+	assert.True(t, sphere.FAI.InitialBearingTo(center, inner[1]) > 0)
+}
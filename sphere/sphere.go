@@ -11,8 +11,19 @@ import (
 const (
 	degrees = 180 / math.Pi
 	radians = math.Pi / 180
+	epsilon = 1e-12
 )
 
+// A Geodesic calculates coordinates, distances, and bearings on a model of the
+// Earth. It is implemented by T and by
+// github.com/twpayne/go-kml/v3/ellipsoid.T.
+type Geodesic interface {
+	Circle(center kml.Coordinate, radius, maxErr float64) []kml.Coordinate
+	Distance(c1, c2 kml.Coordinate) float64
+	InitialBearingTo(c1, c2 kml.Coordinate) float64
+	Offset(origin kml.Coordinate, distance, bearing float64) kml.Coordinate
+}
+
 // A T is a sphere of radius R.
 type T struct {
 	R float64
@@ -44,7 +55,7 @@ func (t T) Offset(origin kml.Coordinate, distance, bearing float64) kml.Coordina
 // Circle returns an array of kml.Coordinates that approximate a circle of the
 // given radius centered on center with a maximum error of maxErr.
 func (t T) Circle(center kml.Coordinate, radius, maxErr float64) []kml.Coordinate {
-	numVertices := int(math.Ceil(math.Pi / math.Acos((radius-maxErr)/(radius+maxErr))))
+	numVertices := CircleVertices(radius, maxErr)
 	cs := make([]kml.Coordinate, numVertices+1)
 	for i := range numVertices {
 		cs[i] = t.Offset(center, radius, 360*float64(i)/float64(numVertices))
@@ -53,6 +64,12 @@ func (t T) Circle(center kml.Coordinate, radius, maxErr float64) []kml.Coordinat
 	return cs
 }
 
+// Distance returns the great circle distance between c1 and c2. It is
+// equivalent to HaversineDistance. Altitude is ignored.
+func (t T) Distance(c1, c2 kml.Coordinate) float64 {
+	return t.HaversineDistance(c1, c2)
+}
+
 // HaversineDistance returns the great circle distance between c1 and c2 using
 // the Haversine formula. Altitude is ignored.
 func (t T) HaversineDistance(c1, c2 kml.Coordinate) float64 {
@@ -75,3 +92,273 @@ func (t T) InitialBearingTo(c1, c2 kml.Coordinate) float64 {
 	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(deltaLon)
 	return math.Atan2(y, x) * degrees
 }
+
+// AlongTrackDistance returns the distance from start along the great circle
+// path from start to end to the point closest to c. The result is negative if
+// the closest point is behind start. Altitude is ignored.
+func (t T) AlongTrackDistance(c, start, end kml.Coordinate) float64 {
+	delta13 := t.HaversineDistance(start, c) / t.R
+	theta12 := t.InitialBearingTo(start, end) * radians
+	theta13 := t.InitialBearingTo(start, c) * radians
+	deltaXT := math.Asin(math.Sin(delta13) * math.Sin(theta13-theta12))
+	deltaAT := math.Acos(clamp(math.Cos(delta13)/math.Cos(deltaXT), -1, 1))
+	return math.Copysign(deltaAT, math.Cos(theta12-theta13)) * t.R
+}
+
+// Area returns the area of the polygon with the given outer ring and inner
+// rings, with edges following great circles. Rings may be open or closed and
+// in either winding order. Altitude is ignored.
+func (t T) Area(outer []kml.Coordinate, inners ...[]kml.Coordinate) float64 {
+	area := t.ringArea(outer)
+	for _, inner := range inners {
+		area -= t.ringArea(inner)
+	}
+	return area
+}
+
+// Contains returns if c is inside the polygon with the given outer ring and
+// inner rings, with edges following great circles. Coordinates on a vertex of
+// a ring are considered to be on that ring. Rings may be open or closed and in
+// either winding order. Altitude is ignored.
+func (t T) Contains(c kml.Coordinate, outer []kml.Coordinate, inners ...[]kml.Coordinate) bool {
+	if !t.ringContains(outer, c) {
+		return false
+	}
+	for _, inner := range inners {
+		if t.ringContains(inner, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// CrossTrackDistance returns the distance from c to the great circle passing
+// through start and end. The result is positive if c is to the right of the
+// path and negative if it is to the left. Altitude is ignored.
+func (t T) CrossTrackDistance(c, start, end kml.Coordinate) float64 {
+	delta13 := t.HaversineDistance(start, c) / t.R
+	theta12 := t.InitialBearingTo(start, end) * radians
+	theta13 := t.InitialBearingTo(start, c) * radians
+	return math.Asin(math.Sin(delta13)*math.Sin(theta13-theta12)) * t.R
+}
+
+// DistanceToPath returns the shortest distance from c to the path cs.
+// Altitude is ignored.
+func (t T) DistanceToPath(c kml.Coordinate, cs []kml.Coordinate) float64 {
+	nearestPoint, _ := t.NearestPointOnPath(c, cs)
+	return t.HaversineDistance(c, nearestPoint)
+}
+
+// DistanceToSegment returns the shortest distance from c to the great circle
+// segment from start to end. Altitude is ignored.
+func (t T) DistanceToSegment(c, start, end kml.Coordinate) float64 {
+	return t.HaversineDistance(c, t.NearestPointOnSegment(c, start, end))
+}
+
+// FinalBearingTo returns the final bearing on arriving at c2 from c1. Altitude
+// is ignored.
+func (t T) FinalBearingTo(c1, c2 kml.Coordinate) float64 {
+	bearing := t.InitialBearingTo(c2, c1)
+	if bearing <= 0 {
+		return bearing + 180
+	}
+	return bearing - 180
+}
+
+// IntermediatePoint returns the point at fraction f along the great circle
+// path from c1 to c2. Altitude is interpolated linearly.
+func (t T) IntermediatePoint(c1, c2 kml.Coordinate, f float64) kml.Coordinate {
+	delta := t.HaversineDistance(c1, c2) / t.R
+	if delta == 0 {
+		return c1
+	}
+	lat1, lon1 := c1.Lat*radians, c1.Lon*radians
+	lat2, lon2 := c2.Lat*radians, c2.Lon*radians
+	a := math.Sin((1-f)*delta) / math.Sin(delta)
+	b := math.Sin(f*delta) / math.Sin(delta)
+	x := a*math.Cos(lat1)*math.Cos(lon1) + b*math.Cos(lat2)*math.Cos(lon2)
+	y := a*math.Cos(lat1)*math.Sin(lon1) + b*math.Cos(lat2)*math.Sin(lon2)
+	z := a*math.Sin(lat1) + b*math.Sin(lat2)
+	return kml.Coordinate{
+		Lon: math.Atan2(y, x) * degrees,
+		Lat: math.Atan2(z, math.Hypot(x, y)) * degrees,
+		Alt: c1.Alt + f*(c2.Alt-c1.Alt),
+	}
+}
+
+// Intersection returns the intersection of the great circle path starting at
+// c1 with bearing1 and the great circle path starting at c2 with bearing2. It
+// returns false if the intersection is ambiguous or if the paths are
+// identical, including when c1 and c2 are the same point and the bearings are
+// equal or opposite. Otherwise, if c1 and c2 are the same point then it is the
+// intersection. The longitude of the result is normalized to [-180, 180) and
+// its altitude is the altitude of c1.
+func (t T) Intersection(c1 kml.Coordinate, bearing1 float64, c2 kml.Coordinate, bearing2 float64) (kml.Coordinate, bool) {
+	lat1, lon1 := c1.Lat*radians, c1.Lon*radians
+	lat2, lon2 := c2.Lat*radians, c2.Lon*radians
+	theta13 := bearing1 * radians
+	theta23 := bearing2 * radians
+
+	delta12 := t.HaversineDistance(c1, c2) / t.R
+	if delta12 == 0 {
+		if math.Abs(math.Sin(theta13-theta23)) < epsilon {
+			return kml.Coordinate{}, false
+		}
+		return c1, true
+	}
+
+	cosThetaA := (math.Sin(lat2) - math.Sin(lat1)*math.Cos(delta12)) / (math.Sin(delta12) * math.Cos(lat1))
+	cosThetaB := (math.Sin(lat1) - math.Sin(lat2)*math.Cos(delta12)) / (math.Sin(delta12) * math.Cos(lat2))
+	thetaA := math.Acos(clamp(cosThetaA, -1, 1))
+	thetaB := math.Acos(clamp(cosThetaB, -1, 1))
+	var theta12, theta21 float64
+	if math.Sin(lon2-lon1) > 0 {
+		theta12, theta21 = thetaA, 2*math.Pi-thetaB
+	} else {
+		theta12, theta21 = 2*math.Pi-thetaA, thetaB
+	}
+
+	alpha1 := theta13 - theta12
+	alpha2 := theta21 - theta23
+	if math.Abs(math.Sin(alpha1)) < epsilon && math.Abs(math.Sin(alpha2)) < epsilon {
+		return kml.Coordinate{}, false
+	}
+	if math.Sin(alpha1)*math.Sin(alpha2) < 0 {
+		return kml.Coordinate{}, false
+	}
+
+	cosAlpha3 := -math.Cos(alpha1)*math.Cos(alpha2) + math.Sin(alpha1)*math.Sin(alpha2)*math.Cos(delta12)
+	delta13 := math.Atan2(math.Sin(delta12)*math.Sin(alpha1)*math.Sin(alpha2), math.Cos(alpha2)+math.Cos(alpha1)*cosAlpha3)
+	lat3 := math.Asin(clamp(math.Sin(lat1)*math.Cos(delta13)+math.Cos(lat1)*math.Sin(delta13)*math.Cos(theta13), -1, 1))
+	deltaLon13 := math.Atan2(math.Sin(theta13)*math.Sin(delta13)*math.Cos(lat1), math.Cos(delta13)-math.Sin(lat1)*math.Sin(lat3))
+	return kml.Coordinate{
+		Lon: NormalizeLon((lon1 + deltaLon13) * degrees),
+		Lat: lat3 * degrees,
+		Alt: c1.Alt,
+	}, true
+}
+
+// Length returns the total great circle length of the path cs. Altitude is
+// ignored.
+func (t T) Length(cs []kml.Coordinate) float64 {
+	length := 0.0
+	for i := 1; i < len(cs); i++ {
+		length += t.HaversineDistance(cs[i-1], cs[i])
+	}
+	return length
+}
+
+// Midpoint returns the point half way along the great circle path between c1
+// and c2. Altitude is averaged.
+func (t T) Midpoint(c1, c2 kml.Coordinate) kml.Coordinate {
+	lat1, lon1 := c1.Lat*radians, c1.Lon*radians
+	lat2 := c2.Lat * radians
+	deltaLon := (c2.Lon - c1.Lon) * radians
+	bx := math.Cos(lat2) * math.Cos(deltaLon)
+	by := math.Cos(lat2) * math.Sin(deltaLon)
+	return kml.Coordinate{
+		Lon: (lon1 + math.Atan2(by, math.Cos(lat1)+bx)) * degrees,
+		Lat: math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Hypot(math.Cos(lat1)+bx, by)) * degrees,
+		Alt: (c1.Alt + c2.Alt) / 2,
+	}
+}
+
+// NearestPointOnPath returns the point on the path cs that is closest to c and
+// the index of the start of the segment containing it. The altitude of the
+// result is interpolated linearly along the segment.
+func (t T) NearestPointOnPath(c kml.Coordinate, cs []kml.Coordinate) (kml.Coordinate, int) {
+	switch len(cs) {
+	case 0:
+		return kml.Coordinate{}, -1
+	case 1:
+		return cs[0], 0
+	}
+	var nearestPoint kml.Coordinate
+	nearestDistance, nearestIndex := math.Inf(1), -1
+	for i := 1; i < len(cs); i++ {
+		point := t.NearestPointOnSegment(c, cs[i-1], cs[i])
+		if distance := t.HaversineDistance(c, point); distance < nearestDistance {
+			nearestPoint, nearestDistance, nearestIndex = point, distance, i-1
+		}
+	}
+	return nearestPoint, nearestIndex
+}
+
+// NearestPointOnSegment returns the point on the great circle segment from
+// start to end that is closest to c. The altitude of the result is
+// interpolated linearly between start and end.
+func (t T) NearestPointOnSegment(c, start, end kml.Coordinate) kml.Coordinate {
+	length := t.HaversineDistance(start, end)
+	if length == 0 {
+		return start
+	}
+	alongTrackDistance := t.AlongTrackDistance(c, start, end)
+	switch {
+	case alongTrackDistance <= 0:
+		return start
+	case alongTrackDistance >= length:
+		return end
+	default:
+		return t.IntermediatePoint(start, end, alongTrackDistance/length)
+	}
+}
+
+// ringArea returns the absolute area of the ring cs.
+func (t T) ringArea(cs []kml.Coordinate) float64 {
+	return math.Abs(ringExcess(cs)) * t.R * t.R
+}
+
+// ringContains returns if c is inside the ring cs by summing the angles
+// subtended by each edge at c.
+func (t T) ringContains(cs []kml.Coordinate, c kml.Coordinate) bool {
+	n := len(cs)
+	if n > 1 && cs[0] == cs[n-1] {
+		n--
+	}
+	if n < 3 {
+		return false
+	}
+	sum := 0.0
+	prevBearing := t.InitialBearingTo(c, cs[n-1])
+	for i := range n {
+		if cs[i].Lon == c.Lon && cs[i].Lat == c.Lat {
+			return true
+		}
+		bearing := t.InitialBearingTo(c, cs[i])
+		sum += math.Remainder(bearing-prevBearing, 360)
+		prevBearing = bearing
+	}
+	return math.Abs(sum) > 180
+}
+
+// ringExcess returns the signed spherical excess of the ring cs, in
+// steradians, by summing the spherical excess of each edge. The result is
+// negative if cs is counter-clockwise.
+func ringExcess(cs []kml.Coordinate) float64 {
+	n := len(cs)
+	if n > 1 && cs[0] == cs[n-1] {
+		n--
+	}
+	if n < 3 {
+		return 0
+	}
+	excess := 0.0
+	for i := range n {
+		c1, c2 := cs[i], cs[(i+1)%n]
+		tanLat1 := math.Tan(c1.Lat * radians / 2)
+		tanLat2 := math.Tan(c2.Lat * radians / 2)
+		deltaLon := (c2.Lon - c1.Lon) * radians
+		excess += 2 * math.Atan2(math.Tan(deltaLon/2)*(tanLat1+tanLat2), 1+tanLat1*tanLat2)
+	}
+	return excess
+}
+
+// CircleVertices returns the number of vertices required to approximate a
+// circle of the given radius with a maximum error of maxErr.
+func CircleVertices(radius, maxErr float64) int {
+	return int(math.Ceil(math.Pi / math.Acos((radius-maxErr)/(radius+maxErr))))
+}
+
+func clamp(x, lo, hi float64) float64 {
+	return max(lo, min(x, hi))
+}
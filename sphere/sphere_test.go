@@ -1,6 +1,8 @@
 package sphere_test
 
 import (
+	"math"
+	"slices"
 	"strconv"
 	"testing"
 
@@ -69,3 +71,201 @@ func TestInitialBearingTo(t *testing.T) {
 		})
 	}
 }
+
+func TestSphereLengthAndArea(t *testing.T) {
+	square := []kml.Coordinate{
+		{Lon: 0, Lat: 0},
+		{Lon: 1, Lat: 0},
+		{Lon: 1, Lat: 1},
+		{Lon: 0, Lat: 1},
+		{Lon: 0, Lat: 0},
+	}
+	assertInDelta(t, 4*sphere.FAI.R*math.Pi/180, sphere.FAI.Length(square), 100)
+	assertInDelta(t, 12363997753.67, sphere.FAI.Area(square), 1e-1)
+
+	// Reversing the winding order does not change the area.
+	reversed := slices.Clone(square)
+	slices.Reverse(reversed)
+	assertInDelta(t, sphere.FAI.Area(square), sphere.FAI.Area(reversed), 1e-3)
+
+	// An octant has area pi*R^2/2.
+	octant := []kml.Coordinate{{Lon: 0, Lat: 0}, {Lon: 90, Lat: 0}, {Lon: 0, Lat: 90}}
+	assertInDelta(t, math.Pi/2, sphere.Unit.Area(octant), 1e-12)
+
+	hole := []kml.Coordinate{
+		{Lon: 0.25, Lat: 0.25},
+		{Lon: 0.75, Lat: 0.25},
+		{Lon: 0.75, Lat: 0.75},
+		{Lon: 0.25, Lat: 0.75},
+	}
+	assertInDelta(t, sphere.FAI.Area(square)-sphere.FAI.Area(hole), sphere.FAI.Area(square, hole), 1e-3)
+}
+
+func TestSphereFinalBearingTo(t *testing.T) {
+	for i, tc := range []struct {
+		c1       kml.Coordinate
+		c2       kml.Coordinate
+		expected float64
+	}{
+		{
+			c1:       kml.Coordinate{Lon: 0, Lat: 0},
+			c2:       kml.Coordinate{Lon: 0, Lat: 1},
+			expected: 0,
+		},
+		{
+			c1:       kml.Coordinate{Lon: 0, Lat: 0},
+			c2:       kml.Coordinate{Lon: -1, Lat: 0},
+			expected: -90,
+		},
+		{
+			c1:       kml.Coordinate{Lon: 0, Lat: 45},
+			c2:       kml.Coordinate{Lon: 90, Lat: 45},
+			expected: 125.26438968275465,
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assertInDelta(t, tc.expected, sphere.FAI.FinalBearingTo(tc.c1, tc.c2), 1e-9)
+		})
+	}
+}
+
+func TestSphereMidpointAndIntermediatePoint(t *testing.T) {
+	c1 := kml.Coordinate{Lon: -5.714722, Lat: 50.066389, Alt: 0}
+	c2 := kml.Coordinate{Lon: -3.07, Lat: 58.643889, Alt: 100}
+	midpoint := sphere.FAI.Midpoint(c1, c2)
+	assertInDelta(t, -4.530672, midpoint.Lon, 1e-6)
+	assertInDelta(t, 54.362287, midpoint.Lat, 1e-6)
+	assert.Equal(t, 50, midpoint.Alt)
+
+	intermediatePoint := sphere.FAI.IntermediatePoint(c1, c2, 0.5)
+	assertInDelta(t, midpoint.Lon, intermediatePoint.Lon, 1e-9)
+	assertInDelta(t, midpoint.Lat, intermediatePoint.Lat, 1e-9)
+	assert.Equal(t, 50, intermediatePoint.Alt)
+
+	distance := sphere.FAI.HaversineDistance(c1, c2)
+	assertInDelta(t, distance/4, sphere.FAI.HaversineDistance(c1, sphere.FAI.IntermediatePoint(c1, c2, 0.25)), 1e-6)
+	assert.Equal(t, c1, sphere.FAI.IntermediatePoint(c1, c1, 0.5))
+}
+
+func TestSphereIntersection(t *testing.T) {
+	c, ok := sphere.FAI.Intersection(kml.Coordinate{Lon: 0, Lat: -1}, 0, kml.Coordinate{Lon: -1, Lat: 0}, 90)
+	assert.True(t, ok)
+	assertInDelta(t, 0, c.Lon, 1e-9)
+	assertInDelta(t, 0, c.Lat, 1e-9)
+
+	c, ok = sphere.FAI.Intersection(kml.Coordinate{Lon: 0.2545, Lat: 51.8853}, 108.547, kml.Coordinate{Lon: 2.5735, Lat: 49.0034}, 32.435)
+	assert.True(t, ok)
+	assertInDelta(t, 4.5084, c.Lon, 1e-4)
+	assertInDelta(t, 50.9078, c.Lat, 1e-4)
+
+	c, ok = sphere.FAI.Intersection(kml.Coordinate{Lon: 179.5, Lat: 0}, 90, kml.Coordinate{Lon: -179, Lat: -1}, 0)
+	assert.True(t, ok)
+	assertInDelta(t, -179, c.Lon, 1e-9)
+	assertInDelta(t, 0, c.Lat, 1e-9)
+
+	_, ok = sphere.FAI.Intersection(kml.Coordinate{Lon: 0, Lat: 0}, 0, kml.Coordinate{Lon: 0, Lat: 1}, 0)
+	assert.False(t, ok)
+
+	origin := kml.Coordinate{Lon: 1, Lat: 2, Alt: 3}
+	c, ok = sphere.FAI.Intersection(origin, 10, origin, 100)
+	assert.True(t, ok)
+	assert.Equal(t, origin, c)
+	_, ok = sphere.FAI.Intersection(origin, 10, origin, 10)
+	assert.False(t, ok)
+	_, ok = sphere.FAI.Intersection(origin, 10, origin, 190)
+	assert.False(t, ok)
+}
+
+func TestSphereCrossAndAlongTrackDistance(t *testing.T) {
+	start := kml.Coordinate{Lon: 0, Lat: 0}
+	end := kml.Coordinate{Lon: 10, Lat: 0}
+	c := kml.Coordinate{Lon: 5, Lat: 1}
+	assertInDelta(t, -sphere.FAI.R*math.Pi/180, sphere.FAI.CrossTrackDistance(c, start, end), 1e-6)
+	assertInDelta(t, sphere.FAI.R*5*math.Pi/180, sphere.FAI.AlongTrackDistance(c, start, end), 1e-6)
+	assertInDelta(t, sphere.FAI.R*math.Pi/180, sphere.FAI.CrossTrackDistance(kml.Coordinate{Lon: 5, Lat: -1}, start, end), 1e-6)
+	assertInDelta(t, -sphere.FAI.R*math.Pi/180, sphere.FAI.AlongTrackDistance(kml.Coordinate{Lon: -1, Lat: 0}, start, end), 1e-6)
+}
+
+func TestSphereContains(t *testing.T) {
+	center := kml.Coordinate{Lon: 13.631333, Lat: 46.438500}
+	cylinder := sphere.FAI.Circle(center, 400, 1)
+	for i, tc := range []struct {
+		c        kml.Coordinate
+		outer    []kml.Coordinate
+		inners   [][]kml.Coordinate
+		expected bool
+	}{
+		{
+			c:        center,
+			outer:    cylinder,
+			expected: true,
+		},
+		{
+			c:        sphere.FAI.Offset(center, 399, 45),
+			outer:    cylinder,
+			expected: true,
+		},
+		{
+			c:        sphere.FAI.Offset(center, 401, 45),
+			outer:    cylinder,
+			expected: false,
+		},
+		{
+			c:        center,
+			outer:    cylinder,
+			inners:   [][]kml.Coordinate{sphere.FAI.Circle(center, 100, 1)},
+			expected: false,
+		},
+		{
+			c:        sphere.FAI.Offset(center, 200, 0),
+			outer:    cylinder,
+			inners:   [][]kml.Coordinate{sphere.FAI.Circle(center, 100, 1)},
+			expected: true,
+		},
+		{
+			c:        kml.Coordinate{Lon: 179.5, Lat: 0.5},
+			outer:    []kml.Coordinate{{Lon: 179, Lat: 0}, {Lon: -179, Lat: 0}, {Lon: -179, Lat: 1}, {Lon: 179, Lat: 1}},
+			expected: true,
+		},
+		{
+			c:        kml.Coordinate{Lon: 0, Lat: 0.5},
+			outer:    []kml.Coordinate{{Lon: 179, Lat: 0}, {Lon: -179, Lat: 0}, {Lon: -179, Lat: 1}, {Lon: 179, Lat: 1}},
+			expected: false,
+		},
+		{
+			c:        kml.Coordinate{Lon: 179, Lat: 0},
+			outer:    []kml.Coordinate{{Lon: 179, Lat: 0}, {Lon: -179, Lat: 0}, {Lon: -179, Lat: 1}, {Lon: 179, Lat: 1}},
+			expected: true,
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, sphere.FAI.Contains(tc.c, tc.outer, tc.inners...))
+		})
+	}
+}
+
+func TestSphereNearestPoint(t *testing.T) {
+	path := []kml.Coordinate{
+		{Lon: 0, Lat: 0, Alt: 0},
+		{Lon: 1, Lat: 0, Alt: 100},
+		{Lon: 1, Lat: 1, Alt: 200},
+	}
+
+	nearestPoint, index := sphere.FAI.NearestPointOnPath(kml.Coordinate{Lon: 0.5, Lat: -0.1}, path)
+	assert.Equal(t, 0, index)
+	assertInDelta(t, 0.5, nearestPoint.Lon, 1e-9)
+	assertInDelta(t, 0, nearestPoint.Lat, 1e-9)
+	assertInDelta(t, 50, nearestPoint.Alt, 1e-6)
+	assertInDelta(t, sphere.FAI.R*0.1*math.Pi/180, sphere.FAI.DistanceToPath(kml.Coordinate{Lon: 0.5, Lat: -0.1}, path), 1e-6)
+
+	nearestPoint, index = sphere.FAI.NearestPointOnPath(kml.Coordinate{Lon: 2, Lat: 2}, path)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, path[2], nearestPoint)
+
+	assert.Equal(t, path[0], sphere.FAI.NearestPointOnSegment(kml.Coordinate{Lon: -1, Lat: 0.1}, path[0], path[1]))
+	assert.Equal(t, path[1], sphere.FAI.NearestPointOnSegment(kml.Coordinate{Lon: 2, Lat: 0.1}, path[0], path[1]))
+	assertInDelta(t, sphere.FAI.HaversineDistance(path[0], kml.Coordinate{Lon: -1, Lat: 0}), sphere.FAI.DistanceToSegment(kml.Coordinate{Lon: -1, Lat: 0}, path[0], path[1]), 1e-9)
+
+	_, index = sphere.FAI.NearestPointOnPath(path[0], nil)
+	assert.Equal(t, -1, index)
+}
@@ -0,0 +1,39 @@
+package kml
+
+import "errors"
+
+// SkipChildren is used as a return value from a WalkFunc to indicate that the
+// children of the current element should not be visited.
+var SkipChildren = errors.New("skip children") //nolint:errname,revive,staticcheck
+
+// A WalkFunc is called by Walk for each element visited.
+type WalkFunc func(element Element) error
+
+// A parentElement is an element whose children can be walked.
+type parentElement interface {
+	children() []Element
+}
+
+// Walk walks the tree rooted at element in depth-first order, calling walkFunc
+// for each element, including element itself. If walkFunc returns
+// SkipChildren then the children of the current element are skipped. Any
+// other non-nil error stops the walk and is returned.
+func Walk(element Element, walkFunc WalkFunc) error {
+	if element == nil {
+		return nil
+	}
+	switch err := walkFunc(element); {
+	case errors.Is(err, SkipChildren):
+		return nil
+	case err != nil:
+		return err
+	}
+	if parentElement, ok := element.(parentElement); ok {
+		for _, child := range parentElement.children() {
+			if err := Walk(child, walkFunc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
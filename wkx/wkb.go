@@ -0,0 +1,285 @@
+package wkx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// WKB byte orders.
+const (
+	wkbXDR = 0 // Big endian.
+	wkbNDR = 1 // Little endian.
+)
+
+// EWKB type flags.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// wkbNaN is the representation of NaN used for the coordinates of empty points.
+const wkbNaN = 0x7ff8000000000000
+
+var errUnexpectedEOF = errors.New("wkb: unexpected end of input")
+
+// A wkbDecoder decodes WKB.
+type wkbDecoder struct {
+	builder
+	data []byte
+	pos  int
+	srid int
+}
+
+// DecodeWKB decodes the WKB or EWKB geometry data. Both ISO and EWKB Z and M
+// type codes are supported. It returns the SRID, which is zero if data does
+// not include one. The geometry is returned as a kml.ParentElement, as for
+// DecodeWKT. Polygons whose rings are all empty are decoded as empty Polygons,
+// and other Polygons with empty rings are rejected.
+func DecodeWKB(data []byte) (kml.ParentElement, int, error) {
+	d := &wkbDecoder{data: data}
+	element, _, err := d.geometry(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if d.pos != len(d.data) {
+		return nil, 0, fmt.Errorf("wkb: %d bytes of trailing data", len(d.data)-d.pos)
+	}
+	d.finish()
+	return element, d.srid, nil
+}
+
+// EncodeWKB returns the WKB encoding of the KML geometry element element using
+// byteOrder, with the same mapping as EncodeWKT. Altitudes are encoded as Z
+// values with ISO type codes if any coordinate has an altitude.
+func EncodeWKB(element kml.Element, byteOrder binary.AppendByteOrder) ([]byte, error) {
+	var e encoder
+	g, err := e.geometry(element, 0)
+	if err != nil {
+		return nil, err
+	}
+	return appendWKB(nil, byteOrder, g, e.z), nil
+}
+
+// geometry decodes a geometry and returns it and its type.
+func (d *wkbDecoder) geometry(depth int) (kml.ParentElement, uint32, error) {
+	if depth > maxDepth {
+		return nil, 0, fmt.Errorf("wkb: maximum depth %d exceeded", maxDepth)
+	}
+	if d.pos+5 > len(d.data) {
+		return nil, 0, errUnexpectedEOF
+	}
+	var byteOrder binary.ByteOrder
+	switch d.data[d.pos] {
+	case wkbXDR:
+		byteOrder = binary.BigEndian
+	case wkbNDR:
+		byteOrder = binary.LittleEndian
+	default:
+		return nil, 0, fmt.Errorf("wkb: invalid byte order %d", d.data[d.pos])
+	}
+	d.pos++
+	typ := byteOrder.Uint32(d.data[d.pos:])
+	d.pos += 4
+	l := layout{
+		z: typ&ewkbZ != 0,
+		m: typ&ewkbM != 0,
+	}
+	hasSRID := typ&ewkbSRID != 0
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	switch typ / 1000 {
+	case 1:
+		l.z = true
+	case 2:
+		l.m = true
+	case 3:
+		l.z, l.m = true, true
+	}
+	typ %= 1000
+	if hasSRID {
+		srid, err := d.uint32(byteOrder)
+		if err != nil {
+			return nil, 0, err
+		}
+		if depth == 0 {
+			d.srid = int(srid)
+		}
+	}
+
+	switch typ {
+	case pointType:
+		offset := len(d.flatCoords)
+		if err := d.floats(byteOrder, l.stride()); err != nil {
+			return nil, 0, err
+		}
+		if math.IsNaN(d.flatCoords[offset]) && math.IsNaN(d.flatCoords[offset+1]) {
+			d.flatCoords = d.flatCoords[:offset]
+			return kml.Point(), typ, nil
+		}
+		return kml.Point(d.coordinates(offset, l)), typ, nil
+	case lineStringType:
+		coordinates, err := d.coordinatesList(byteOrder, l)
+		if err != nil {
+			return nil, 0, err
+		}
+		if coordinates == nil {
+			return kml.LineString(), typ, nil
+		}
+		return kml.LineString(coordinates), typ, nil
+	case polygonType:
+		n, err := d.count(byteOrder, 4)
+		if err != nil {
+			return nil, 0, err
+		}
+		// Empty rings are only allowed if all rings are empty, so that the
+		// positions of the outer and inner rings are kept.
+		rings := make([]kml.Element, 0, n)
+		empty := 0
+		for range n {
+			ring, err := d.coordinatesList(byteOrder, l)
+			if err != nil {
+				return nil, 0, err
+			}
+			if ring == nil {
+				empty++
+			} else {
+				rings = append(rings, ring)
+			}
+		}
+		if empty > 0 && len(rings) > 0 {
+			return nil, 0, errors.New("wkb: empty ring in non-empty POLYGON")
+		}
+		return polygon(rings), typ, nil
+	case multiPointType, multiLineStringType, multiPolygonType, geometryCollectionType:
+		n, err := d.count(byteOrder, 5)
+		if err != nil {
+			return nil, 0, err
+		}
+		children := make([]kml.Element, 0, n)
+		for range n {
+			child, childType, err := d.geometry(depth + 1)
+			if err != nil {
+				return nil, 0, err
+			}
+			if typ != geometryCollectionType && childType != typ-3 {
+				return nil, 0, fmt.Errorf("wkb: %s in %s", typeNames[childType], typeNames[typ])
+			}
+			children = append(children, child)
+		}
+		return kml.MultiGeometry(children...), typ, nil
+	default:
+		return nil, 0, fmt.Errorf("wkb: unsupported geometry type %d", typ)
+	}
+}
+
+// coordinatesList decodes a count followed by that many coordinates. It
+// returns nil if the count is zero.
+func (d *wkbDecoder) coordinatesList(byteOrder binary.ByteOrder, l layout) (*kml.CoordinatesFlatElement, error) {
+	n, err := d.count(byteOrder, 8*l.stride())
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	offset := len(d.flatCoords)
+	if err := d.floats(byteOrder, n*l.stride()); err != nil {
+		return nil, err
+	}
+	return d.coordinates(offset, l), nil
+}
+
+// count decodes a count of items, each of which is at least size bytes, and
+// checks that there is enough data remaining.
+func (d *wkbDecoder) count(byteOrder binary.ByteOrder, size int) (int, error) {
+	n, err := d.uint32(byteOrder)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(size) > uint64(len(d.data)-d.pos) {
+		return 0, errUnexpectedEOF
+	}
+	return int(n), nil
+}
+
+// floats decodes n float64s and appends them to d.flatCoords.
+func (d *wkbDecoder) floats(byteOrder binary.ByteOrder, n int) error {
+	if d.pos+8*n > len(d.data) {
+		return errUnexpectedEOF
+	}
+	for range n {
+		d.flatCoords = append(d.flatCoords, math.Float64frombits(byteOrder.Uint64(d.data[d.pos:])))
+		d.pos += 8
+	}
+	return nil
+}
+
+// uint32 decodes a uint32.
+func (d *wkbDecoder) uint32(byteOrder binary.ByteOrder) (uint32, error) {
+	if d.pos+4 > len(d.data) {
+		return 0, errUnexpectedEOF
+	}
+	value := byteOrder.Uint32(d.data[d.pos:])
+	d.pos += 4
+	return value, nil
+}
+
+// appendWKB appends the WKB encoding of g to data.
+func appendWKB(data []byte, byteOrder binary.AppendByteOrder, g *geometry, z bool) []byte {
+	if byteOrder == binary.BigEndian {
+		data = append(data, wkbXDR)
+	} else {
+		data = append(data, wkbNDR)
+	}
+	typ := g.typ
+	if z {
+		typ += 1000
+	}
+	data = byteOrder.AppendUint32(data, typ)
+	switch g.typ {
+	case pointType:
+		if g.empty() {
+			data = byteOrder.AppendUint64(data, wkbNaN)
+			data = byteOrder.AppendUint64(data, wkbNaN)
+			if z {
+				data = byteOrder.AppendUint64(data, wkbNaN)
+			}
+			return data
+		}
+		return appendWKBCoordinate(data, byteOrder, g.rings[0][0], z)
+	case lineStringType, polygonType:
+		if g.typ == polygonType {
+			data = byteOrder.AppendUint32(data, uint32(len(g.rings))) //nolint:gosec
+		}
+		for _, ring := range g.rings {
+			data = byteOrder.AppendUint32(data, uint32(len(ring))) //nolint:gosec
+			for _, c := range ring {
+				data = appendWKBCoordinate(data, byteOrder, c, z)
+			}
+		}
+		if g.typ == lineStringType && g.empty() {
+			data = byteOrder.AppendUint32(data, 0)
+		}
+		return data
+	default:
+		data = byteOrder.AppendUint32(data, uint32(len(g.children))) //nolint:gosec
+		for _, child := range g.children {
+			data = appendWKB(data, byteOrder, child, z)
+		}
+		return data
+	}
+}
+
+// appendWKBCoordinate appends c to data.
+func appendWKBCoordinate(data []byte, byteOrder binary.AppendByteOrder, c kml.Coordinate, z bool) []byte {
+	data = byteOrder.AppendUint64(data, math.Float64bits(c.Lon))
+	data = byteOrder.AppendUint64(data, math.Float64bits(c.Lat))
+	if z {
+		data = byteOrder.AppendUint64(data, math.Float64bits(c.Alt))
+	}
+	return data
+}
@@ -0,0 +1,124 @@
+package wkx_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/wkx"
+)
+
+func TestWKB(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		wkb         string
+		expectedXML string
+		expectedWKB string
+		srid        int
+	}{
+		{
+			name:        "point",
+			wkb:         "0101000000000000000000f03f0000000000000040",
+			expectedXML: "<Point><coordinates>1,2</coordinates></Point>",
+		},
+		{
+			name:        "point_empty",
+			wkb:         "0101000000000000000000f87f000000000000f87f",
+			expectedXML: "<Point></Point>",
+		},
+		{
+			name:        "ewkb_srid",
+			wkb:         "0101000020e6100000000000000000f03f0000000000000040",
+			expectedXML: "<Point><coordinates>1,2</coordinates></Point>",
+			expectedWKB: "0101000000000000000000f03f0000000000000040",
+			srid:        4326,
+		},
+		{
+			name:        "iso_point_z_big_endian",
+			wkb:         "00000003e93ff000000000000040000000000000004008000000000000",
+			expectedXML: "<Point><coordinates>1,2,3</coordinates></Point>",
+			expectedWKB: "01e9030000000000000000f03f00000000000000400000000000000840",
+		},
+		{
+			name:        "ewkb_linestring_zm",
+			wkb:         "01020000c002000000000000000000f03f000000000000004000000000000008400000000000001040000000000000144000000000000018400000000000001c400000000000002040",
+			expectedXML: "<LineString><coordinates>1,2,3 5,6,7</coordinates></LineString>",
+			expectedWKB: "01ea03000002000000000000000000f03f00000000000000400000000000000840000000000000144000000000000018400000000000001c40",
+		},
+		{
+			name:        "polygon",
+			wkb:         "0103000000010000000400000000000000000000000000000000000000000000000000f03f0000000000000000000000000000f03f000000000000f03f00000000000000000000000000000000",
+			expectedXML: "<Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon>",
+		},
+		{
+			name:        "multipoint_mixed_byte_order",
+			wkb:         "0104000000020000000101000000000000000000f03f0000000000000040000000000140080000000000004010000000000000",
+			expectedXML: "<MultiGeometry><Point><coordinates>1,2</coordinates></Point><Point><coordinates>3,4</coordinates></Point></MultiGeometry>",
+			expectedWKB: "0104000000020000000101000000000000000000f03f0000000000000040010100000000000000000008400000000000001040",
+		},
+		{
+			name:        "geometrycollection",
+			wkb:         "0107000000020000000101000000000000000000f03f0000000000000040010200000000000000",
+			expectedXML: "<MultiGeometry><Point><coordinates>1,2</coordinates></Point><LineString></LineString></MultiGeometry>",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.wkb)
+			assert.NoError(t, err)
+			element, srid, err := wkx.DecodeWKB(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.srid, srid)
+			actualXML, err := xml.Marshal(element)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedXML, string(actualXML))
+			actualWKB, err := wkx.EncodeWKB(element, binary.LittleEndian)
+			assert.NoError(t, err)
+			expectedWKB := tc.expectedWKB
+			if expectedWKB == "" {
+				expectedWKB = tc.wkb
+			}
+			assert.Equal(t, expectedWKB, hex.EncodeToString(actualWKB))
+		})
+	}
+}
+
+func TestDecodeWKBErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		wkb  string
+	}{
+		{name: "empty", wkb: ""},
+		{name: "invalid_byte_order", wkb: "0201000000000000000000f03f0000000000000040"},
+		{name: "truncated", wkb: "0101000000000000000000f03f00000000000000"},
+		{name: "trailing_data", wkb: "0101000000000000000000f03f000000000000004000"},
+		{name: "huge_count", wkb: "0102000000ffffffff"},
+		{name: "unsupported_type", wkb: "0111000000"},
+		{name: "linestring_in_multipoint", wkb: "010400000001000000010200000000000000"},
+		{name: "empty_exterior_ring", wkb: "01030000000200000000000000040000000000000000000000000000000000000000000000000000f03f0000000000000000000000000000f03f000000000000f03f00000000000000000000000000000000"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.wkb)
+			assert.NoError(t, err)
+			_, _, err = wkx.DecodeWKB(data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeWKBEmptyRings(t *testing.T) {
+	data, err := hex.DecodeString("01030000000200000000000000" + "00000000")
+	assert.NoError(t, err)
+	element, _, err := wkx.DecodeWKB(data)
+	assert.NoError(t, err)
+	assert.Equal(t, kml.ParentElement(kml.Polygon()), element)
+}
+
+func TestEncodeWKBBigEndian(t *testing.T) {
+	actual, err := wkx.EncodeWKB(kml.Point(kml.Coordinates(kml.Coordinate{Lon: 1, Lat: 2, Alt: 3})), binary.BigEndian)
+	assert.NoError(t, err)
+	assert.Equal(t, "00000003e93ff000000000000040000000000000004008000000000000", hex.EncodeToString(actual))
+}
@@ -0,0 +1,439 @@
+package wkx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// A wktDecoder decodes WKT.
+type wktDecoder struct {
+	builder
+	s   string
+	pos int
+}
+
+// DecodeWKT decodes the WKT or EWKT geometry s. It returns the SRID, which is
+// zero if s does not include one. The geometry is returned as a
+// kml.ParentElement so that children such as kml.AltitudeMode can be added.
+func DecodeWKT(s string) (kml.ParentElement, int, error) {
+	d := &wktDecoder{s: s}
+	srid := 0
+	if strings.EqualFold(d.peek(), "SRID") {
+		d.next()
+		if err := d.expect("="); err != nil {
+			return nil, 0, err
+		}
+		token := d.next()
+		var err error
+		if srid, err = strconv.Atoi(token); err != nil {
+			return nil, 0, d.errorf("invalid SRID %q", token)
+		}
+		if err := d.expect(";"); err != nil {
+			return nil, 0, err
+		}
+	}
+	element, err := d.geometry(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if token := d.next(); token != "" {
+		return nil, 0, d.errorf("unexpected %q", token)
+	}
+	d.finish()
+	return element, srid, nil
+}
+
+// EncodeWKT returns the WKT encoding of the KML geometry element element. Point,
+// LineString, LinearRing, Polygon, MultiGeometry, gx:Track, and gx:MultiTrack
+// elements are supported. LinearRings and gx:Tracks are encoded as
+// LINESTRINGs and gx:MultiTracks as MULTILINESTRINGs. MultiGeometries are
+// encoded as MULTIPOINTs, MULTILINESTRINGs, or MULTIPOLYGONs if all their
+// children are of the same type, and GEOMETRYCOLLECTIONs otherwise. Model
+// elements in MultiGeometries are skipped. An empty MultiGeometry is encoded
+// as GEOMETRYCOLLECTION EMPTY, so empty multi geometries such as MULTIPOINT
+// EMPTY do not round trip. Altitudes are encoded as Z values if any coordinate
+// has an altitude.
+func EncodeWKT(element kml.Element) (string, error) {
+	var e encoder
+	g, err := e.geometry(element, 0)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	appendWKT(&builder, g, e.z, true)
+	return builder.String(), nil
+}
+
+// geometry decodes a geometry.
+func (d *wktDecoder) geometry(depth int) (kml.ParentElement, error) {
+	if depth > maxDepth {
+		return nil, d.errorf("maximum depth %d exceeded", maxDepth)
+	}
+	typ, l, known, err := d.typeAndLayout()
+	if err != nil {
+		return nil, err
+	}
+	return d.body(typ, &l, known, depth)
+}
+
+// body decodes the body of a geometry of type typ.
+func (d *wktDecoder) body(typ uint32, l *layout, known bool, depth int) (kml.ParentElement, error) {
+	if empty, err := d.emptyOrOpen(); err != nil {
+		return nil, err
+	} else if empty {
+		return emptyElement(typ), nil
+	}
+	var element kml.ParentElement
+	switch typ {
+	case pointType:
+		offset := len(d.flatCoords)
+		if err := d.coordinate(l, &known); err != nil {
+			return nil, err
+		}
+		element = kml.Point(d.coordinates(offset, *l))
+	case lineStringType:
+		coordinates, err := d.coordinatesBody(l, &known)
+		if err != nil {
+			return nil, err
+		}
+		element = kml.LineString(coordinates)
+	case polygonType:
+		rings, err := d.polygonBody(l, &known)
+		if err != nil {
+			return nil, err
+		}
+		element = polygon(rings)
+	case multiPointType:
+		multiGeometry := kml.MultiGeometry()
+		for {
+			switch {
+			case strings.EqualFold(d.peek(), "EMPTY"):
+				d.next()
+				multiGeometry.Append(kml.Point())
+			case d.peek() == "(":
+				point, err := d.body(pointType, l, known, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				known = known || !isEmpty(point)
+				multiGeometry.Append(point)
+			default:
+				offset := len(d.flatCoords)
+				if err := d.coordinate(l, &known); err != nil {
+					return nil, err
+				}
+				multiGeometry.Append(kml.Point(d.coordinates(offset, *l)))
+			}
+			if !d.comma() {
+				break
+			}
+		}
+		element = multiGeometry
+	case multiLineStringType, multiPolygonType:
+		childType := uint32(lineStringType)
+		if typ == multiPolygonType {
+			childType = polygonType
+		}
+		multiGeometry := kml.MultiGeometry()
+		for {
+			child, err := d.body(childType, l, known, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			known = known || !isEmpty(child)
+			multiGeometry.Append(child)
+			if !d.comma() {
+				break
+			}
+		}
+		element = multiGeometry
+	case geometryCollectionType:
+		multiGeometry := kml.MultiGeometry()
+		for {
+			child, err := d.geometry(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			multiGeometry.Append(child)
+			if !d.comma() {
+				break
+			}
+		}
+		element = multiGeometry
+	}
+	if err := d.expect(")"); err != nil {
+		return nil, err
+	}
+	return element, nil
+}
+
+// coordinate decodes a single coordinate, determining the layout from the
+// number of values if it is not already known.
+func (d *wktDecoder) coordinate(l *layout, known *bool) error {
+	offset := len(d.flatCoords)
+	for {
+		token := d.peek()
+		if token == "" || strings.ContainsAny(token[:1], "(),;=") {
+			break
+		}
+		d.next()
+		value, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return d.errorf("invalid number %q", token)
+		}
+		d.flatCoords = append(d.flatCoords, value)
+	}
+	n := len(d.flatCoords) - offset
+	if !*known {
+		switch n {
+		case 2:
+		case 3:
+			l.z = true
+		case 4:
+			l.z, l.m = true, true
+		default:
+			return d.errorf("invalid coordinate with %d values", n)
+		}
+		*known = true
+	} else if n != l.stride() {
+		return d.errorf("expected %d values, got %d", l.stride(), n)
+	}
+	return nil
+}
+
+// coordinatesBody decodes a parenthesized list of coordinates, assuming that
+// the opening parenthesis has already been consumed.
+func (d *wktDecoder) coordinatesBody(l *layout, known *bool) (*kml.CoordinatesFlatElement, error) {
+	offset := len(d.flatCoords)
+	for {
+		if err := d.coordinate(l, known); err != nil {
+			return nil, err
+		}
+		if !d.comma() {
+			break
+		}
+	}
+	return d.coordinates(offset, *l), nil
+}
+
+// polygonBody decodes the rings of a polygon, assuming that the opening
+// parenthesis has already been consumed.
+func (d *wktDecoder) polygonBody(l *layout, known *bool) ([]kml.Element, error) {
+	var rings []kml.Element
+	for {
+		if err := d.expect("("); err != nil {
+			return nil, err
+		}
+		ring, err := d.coordinatesBody(l, known)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.expect(")"); err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+		if !d.comma() {
+			break
+		}
+	}
+	return rings, nil
+}
+
+// typeAndLayout decodes a geometry type and its optional Z, M, or ZM
+// dimension, which may be a separate word or a suffix of the type.
+func (d *wktDecoder) typeAndLayout() (uint32, layout, bool, error) {
+	token := d.next()
+	word := strings.ToUpper(token)
+	var dimension string
+	for _, suffix := range []string{"ZM", "Z", "M"} {
+		if base, ok := strings.CutSuffix(word, suffix); ok && wktType(base) != 0 {
+			word, dimension = base, suffix
+			break
+		}
+	}
+	typ := wktType(word)
+	if typ == 0 {
+		return 0, layout{}, false, d.errorf("unknown geometry type %q", token)
+	}
+	if dimension == "" {
+		switch next := strings.ToUpper(d.peek()); next {
+		case "Z", "M", "ZM":
+			d.next()
+			dimension = next
+		}
+	}
+	l := layout{
+		z: strings.Contains(dimension, "Z"),
+		m: strings.Contains(dimension, "M"),
+	}
+	return typ, l, dimension != "", nil
+}
+
+// comma consumes a comma if it is the next token and returns if it did.
+func (d *wktDecoder) comma() bool {
+	if d.peek() == "," {
+		d.next()
+		return true
+	}
+	return false
+}
+
+// emptyOrOpen consumes either EMPTY or an opening parenthesis and returns
+// if it was EMPTY.
+func (d *wktDecoder) emptyOrOpen() (bool, error) {
+	if strings.EqualFold(d.peek(), "EMPTY") {
+		d.next()
+		return true, nil
+	}
+	return false, d.expect("(")
+}
+
+// expect consumes the next token and returns an error if it is not token.
+func (d *wktDecoder) expect(token string) error {
+	if next := d.next(); next != token {
+		if next == "" {
+			return d.errorf("expected %q, got end of input", token)
+		}
+		return d.errorf("expected %q, got %q", token, next)
+	}
+	return nil
+}
+
+// errorf returns an error at the current position.
+func (d *wktDecoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("wkt: offset %d: %s", d.pos, fmt.Sprintf(format, args...))
+}
+
+// next consumes and returns the next token, or the empty string at the end of
+// the input.
+func (d *wktDecoder) next() string {
+	token, end := d.token()
+	d.pos = end
+	return token
+}
+
+// peek returns the next token without consuming it.
+func (d *wktDecoder) peek() string {
+	token, _ := d.token()
+	return token
+}
+
+// token returns the next token and the offset of its end.
+func (d *wktDecoder) token() (string, int) {
+	start := d.pos
+	for start < len(d.s) && isSpace(d.s[start]) {
+		start++
+	}
+	if start == len(d.s) {
+		return "", start
+	}
+	if strings.IndexByte("(),;=", d.s[start]) != -1 {
+		return d.s[start : start+1], start + 1
+	}
+	end := start
+	for end < len(d.s) && !isSpace(d.s[end]) && strings.IndexByte("(),;=", d.s[end]) == -1 {
+		end++
+	}
+	return d.s[start:end], end
+}
+
+// appendWKT appends the WKT encoding of g to builder. If tagged is true then
+// the type is included.
+func appendWKT(builder *strings.Builder, g *geometry, z, tagged bool) {
+	if tagged {
+		builder.WriteString(typeNames[g.typ])
+		if z {
+			builder.WriteString(" Z ")
+		}
+	}
+	if g.empty() {
+		if tagged && !z {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString("EMPTY")
+		return
+	}
+	builder.WriteByte('(')
+	switch g.typ {
+	case pointType, lineStringType:
+		appendWKTCoordinates(builder, g.rings[0], z)
+	case polygonType:
+		for i, ring := range g.rings {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteByte('(')
+			appendWKTCoordinates(builder, ring, z)
+			builder.WriteByte(')')
+		}
+	default:
+		for i, child := range g.children {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			appendWKT(builder, child, z, g.typ == geometryCollectionType)
+		}
+	}
+	builder.WriteByte(')')
+}
+
+// appendWKTCoordinates appends cs to builder.
+func appendWKTCoordinates(builder *strings.Builder, cs []kml.Coordinate, z bool) {
+	for i, c := range cs {
+		if i != 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(strconv.FormatFloat(c.Lon, 'f', -1, 64))
+		builder.WriteByte(' ')
+		builder.WriteString(strconv.FormatFloat(c.Lat, 'f', -1, 64))
+		if z {
+			builder.WriteByte(' ')
+			builder.WriteString(strconv.FormatFloat(c.Alt, 'f', -1, 64))
+		}
+	}
+}
+
+// emptyElement returns an empty element of type typ.
+func emptyElement(typ uint32) kml.ParentElement {
+	switch typ {
+	case pointType:
+		return kml.Point()
+	case lineStringType:
+		return kml.LineString()
+	case polygonType:
+		return kml.Polygon()
+	default:
+		return kml.MultiGeometry()
+	}
+}
+
+// isEmpty returns if element is an empty Point, LineString, or Polygon.
+func isEmpty(element kml.Element) bool {
+	switch element := element.(type) {
+	case *kml.PointElement:
+		return len(element.Children) == 0
+	case *kml.LineStringElement:
+		return len(element.Children) == 0
+	case *kml.PolygonElement:
+		return len(element.Children) == 0
+	default:
+		return false
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// wktType returns the geometry type named name, or zero if there is none.
+func wktType(name string) uint32 {
+	for typ, typeName := range typeNames {
+		if typeName == name {
+			return typ
+		}
+	}
+	return 0
+}
@@ -0,0 +1,202 @@
+package wkx_test
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+
+	"github.com/twpayne/go-kml/v3"
+	"github.com/twpayne/go-kml/v3/wkx"
+)
+
+func TestWKT(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		wkt         string
+		expectedXML string
+		expectedWKT string
+		srid        int
+	}{
+		{
+			name:        "point",
+			wkt:         "POINT (1 2)",
+			expectedXML: "<Point><coordinates>1,2</coordinates></Point>",
+			expectedWKT: "POINT(1 2)",
+		},
+		{
+			name:        "point_empty",
+			wkt:         "POINT EMPTY",
+			expectedXML: "<Point></Point>",
+			expectedWKT: "POINT EMPTY",
+		},
+		{
+			name:        "point_z",
+			wkt:         "POINT Z (1 2 3)",
+			expectedXML: "<Point><coordinates>1,2,3</coordinates></Point>",
+			expectedWKT: "POINT Z (1 2 3)",
+		},
+		{
+			name:        "point_implicit_z",
+			wkt:         "POINT(1 2 3)",
+			expectedXML: "<Point><coordinates>1,2,3</coordinates></Point>",
+			expectedWKT: "POINT Z (1 2 3)",
+		},
+		{
+			name:        "point_m",
+			wkt:         "POINTM(1 2 3)",
+			expectedXML: "<Point><coordinates>1,2</coordinates></Point>",
+			expectedWKT: "POINT(1 2)",
+		},
+		{
+			name:        "point_zm",
+			wkt:         "point zm (1 2 3 4)",
+			expectedXML: "<Point><coordinates>1,2,3</coordinates></Point>",
+			expectedWKT: "POINT Z (1 2 3)",
+		},
+		{
+			name:        "ewkt",
+			wkt:         "SRID=4326;LINESTRING(1 2,3 4)",
+			expectedXML: "<LineString><coordinates>1,2 3,4</coordinates></LineString>",
+			expectedWKT: "LINESTRING(1 2,3 4)",
+			srid:        4326,
+		},
+		{
+			name:        "polygon",
+			wkt:         "POLYGON((0 0,10 0,10 10,0 10,0 0),(1 1,1 2,2 2,1 1))",
+			expectedXML: "<Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 10,0 10,10 0,10 0,0</coordinates></LinearRing></outerBoundaryIs><innerBoundaryIs><LinearRing><coordinates>1,1 1,2 2,2 1,1</coordinates></LinearRing></innerBoundaryIs></Polygon>",
+			expectedWKT: "POLYGON((0 0,10 0,10 10,0 10,0 0),(1 1,1 2,2 2,1 1))",
+		},
+		{
+			name:        "multipoint",
+			wkt:         "MULTIPOINT(1 2, 3 4)",
+			expectedXML: "<MultiGeometry><Point><coordinates>1,2</coordinates></Point><Point><coordinates>3,4</coordinates></Point></MultiGeometry>",
+			expectedWKT: "MULTIPOINT((1 2),(3 4))",
+		},
+		{
+			name:        "multipoint_parenthesized",
+			wkt:         "MULTIPOINT((1 2), EMPTY, (3 4))",
+			expectedXML: "<MultiGeometry><Point><coordinates>1,2</coordinates></Point><Point></Point><Point><coordinates>3,4</coordinates></Point></MultiGeometry>",
+			expectedWKT: "MULTIPOINT((1 2),EMPTY,(3 4))",
+		},
+		{
+			name:        "multilinestring",
+			wkt:         "MULTILINESTRING Z ((1 2 3,4 5 6),(7 8 9,10 11 12))",
+			expectedXML: "<MultiGeometry><LineString><coordinates>1,2,3 4,5,6</coordinates></LineString><LineString><coordinates>7,8,9 10,11,12</coordinates></LineString></MultiGeometry>",
+			expectedWKT: "MULTILINESTRING Z ((1 2 3,4 5 6),(7 8 9,10 11 12))",
+		},
+		{
+			name:        "multipolygon",
+			wkt:         "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),EMPTY)",
+			expectedXML: "<MultiGeometry><Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon><Polygon></Polygon></MultiGeometry>",
+			expectedWKT: "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),EMPTY)",
+		},
+		{
+			name:        "geometrycollection",
+			wkt:         "GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(3 4,5 6),MULTIPOINT EMPTY)",
+			expectedXML: "<MultiGeometry><Point><coordinates>1,2</coordinates></Point><LineString><coordinates>3,4 5,6</coordinates></LineString><MultiGeometry></MultiGeometry></MultiGeometry>",
+			expectedWKT: "GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(3 4,5 6),GEOMETRYCOLLECTION EMPTY)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			element, srid, err := wkx.DecodeWKT(tc.wkt)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.srid, srid)
+			actualXML, err := xml.Marshal(element)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedXML, string(actualXML))
+			actualWKT, err := wkx.EncodeWKT(element)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWKT, actualWKT)
+		})
+	}
+}
+
+func TestDecodeWKTErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"POINT",
+		"POINT(1)",
+		"POINT(1 2",
+		"POINT(1 2) POINT(3 4)",
+		"POINT Z (1 2)",
+		"LINESTRING(1 2,3 4 5)",
+		"POLYGON(1 2,3 4)",
+		"SRID=x;POINT(1 2)",
+		"CIRCLE(1 2)",
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, _, err := wkx.DecodeWKT(s)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncodeWKT(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		element  kml.Element
+		expected string
+	}{
+		{
+			name: "coordinates",
+			element: kml.LineString(
+				kml.AltitudeMode(kml.AltitudeModeAbsolute),
+				kml.Coordinates(kml.Coordinate{Lon: 1, Lat: 2}, kml.Coordinate{Lon: 3, Lat: 4, Alt: 5}),
+			),
+			expected: "LINESTRING Z (1 2 0,3 4 5)",
+		},
+		{
+			name: "coordinates_slice",
+			element: kml.LinearRing(
+				kml.CoordinatesSlice([]float64{0, 0}, []float64{1, 0}, []float64{1, 1}, []float64{0, 0}),
+			),
+			expected: "LINESTRING(0 0,1 0,1 1,0 0)",
+		},
+		{
+			name: "nested_multigeometry",
+			element: kml.MultiGeometry(
+				kml.MultiGeometry(kml.Point(kml.Coordinates(kml.Coordinate{Lon: 1, Lat: 2}))),
+				kml.MultiGeometry(kml.Point(kml.Coordinates(kml.Coordinate{Lon: 3, Lat: 4}))),
+			),
+			expected: "GEOMETRYCOLLECTION(MULTIPOINT((1 2)),MULTIPOINT((3 4)))",
+		},
+		{
+			name: "gx_track",
+			element: kml.MultiGeometry(
+				kml.Model(),
+				kml.GxTrack(
+					kml.When(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+					kml.GxCoord(kml.Coordinate{Lon: 1, Lat: 2, Alt: 3}),
+					kml.GxCoord(kml.Coordinate{Lon: 4, Lat: 5, Alt: 6}),
+				),
+			),
+			expected: "MULTILINESTRING Z ((1 2 3,4 5 6))",
+		},
+		{
+			name: "gx_multi_track",
+			element: kml.GxMultiTrack(
+				kml.GxTrack(kml.GxCoord(kml.Coordinate{Lon: 1, Lat: 2}), kml.GxCoord(kml.Coordinate{Lon: 3, Lat: 4})),
+				kml.GxTrack(),
+			),
+			expected: "MULTILINESTRING((1 2,3 4),EMPTY)",
+		},
+		{
+			name:     "empty_multigeometry",
+			element:  kml.MultiGeometry(kml.Model()),
+			expected: "GEOMETRYCOLLECTION EMPTY",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := wkx.EncodeWKT(tc.element)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err := wkx.EncodeWKT(kml.Placemark())
+	assert.Error(t, err)
+	_, err = wkx.EncodeWKT(kml.Model())
+	assert.Error(t, err)
+}
@@ -0,0 +1,254 @@
+// Package wkx converts between Well-Known Text (WKT) and Well-Known Binary
+// (WKB) geometries and KML geometry elements.
+//
+// Decoded geometries use kml.CoordinatesFlat elements that share a single
+// slice of flat coordinates. Z values become altitudes and M values are
+// discarded. The extended forms used by PostGIS, EWKT and EWKB, are also
+// decoded, in which case the SRID is returned. KML coordinates are always
+// WGS84 longitudes and latitudes, so callers should check the SRID if it
+// matters to them.
+//
+// See https://libgeos.org/specifications/wkt/ and
+// https://libgeos.org/specifications/wkb/.
+package wkx
+
+import (
+	"fmt"
+
+	"github.com/twpayne/go-kml/v3"
+)
+
+// Geometry types.
+const (
+	pointType              = 1
+	lineStringType         = 2
+	polygonType            = 3
+	multiPointType         = 4
+	multiLineStringType    = 5
+	multiPolygonType       = 6
+	geometryCollectionType = 7
+)
+
+// maxDepth is the maximum depth of nested geometry collections.
+const maxDepth = 64
+
+var typeNames = map[uint32]string{
+	pointType:              "POINT",
+	lineStringType:         "LINESTRING",
+	polygonType:            "POLYGON",
+	multiPointType:         "MULTIPOINT",
+	multiLineStringType:    "MULTILINESTRING",
+	multiPolygonType:       "MULTIPOLYGON",
+	geometryCollectionType: "GEOMETRYCOLLECTION",
+}
+
+// A layout describes which values are present in each coordinate.
+type layout struct {
+	z bool
+	m bool
+}
+
+// dim returns the dimension of the kml.CoordinatesFlat elements for l.
+func (l layout) dim() int {
+	if l.z {
+		return 3
+	}
+	return 2
+}
+
+// stride returns the number of values in each coordinate.
+func (l layout) stride() int {
+	stride := 2
+	if l.z {
+		stride++
+	}
+	if l.m {
+		stride++
+	}
+	return stride
+}
+
+// A builder accumulates flat coordinates and the kml.CoordinatesFlat elements
+// that refer to them.
+type builder struct {
+	flatCoords       []float64
+	coordinatesFlats []*kml.CoordinatesFlatElement
+}
+
+// coordinates returns a new kml.CoordinatesFlat element for the flat
+// coordinates from offset to the end of b.flatCoords.
+func (b *builder) coordinates(offset int, l layout) *kml.CoordinatesFlatElement {
+	coordinatesFlat := kml.CoordinatesFlat(nil, offset, len(b.flatCoords), l.stride(), l.dim())
+	b.coordinatesFlats = append(b.coordinatesFlats, coordinatesFlat)
+	return coordinatesFlat
+}
+
+// finish sets the flat coordinates of all elements returned by b.coordinates.
+// It must be called once all coordinates have been appended.
+func (b *builder) finish() {
+	for _, coordinatesFlat := range b.coordinatesFlats {
+		coordinatesFlat.FlatCoords = b.flatCoords
+	}
+}
+
+// polygon returns a new Polygon element with the given rings.
+func polygon(rings []kml.Element) *kml.PolygonElement {
+	if len(rings) == 0 {
+		return kml.Polygon()
+	}
+	children := make([]kml.Element, 0, len(rings))
+	children = append(children, kml.OuterBoundaryIs(kml.LinearRing(rings[0])))
+	for _, ring := range rings[1:] {
+		children = append(children, kml.InnerBoundaryIs(kml.LinearRing(ring)))
+	}
+	return kml.Polygon(children...)
+}
+
+// A geometry is an intermediate representation of a KML geometry element used
+// for encoding.
+type geometry struct {
+	typ      uint32
+	rings    [][]kml.Coordinate
+	children []*geometry
+}
+
+// empty returns if g is empty.
+func (g *geometry) empty() bool {
+	return len(g.rings) == 0 && len(g.children) == 0
+}
+
+// An encoder converts KML geometry elements to geometries.
+type encoder struct {
+	z bool
+}
+
+// geometry returns the geometry corresponding to element.
+func (e *encoder) geometry(element kml.Element, depth int) (*geometry, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("maximum depth %d exceeded", maxDepth)
+	}
+	switch element := element.(type) {
+	case *kml.PointElement:
+		g := &geometry{typ: pointType}
+		if cs := e.coordinates(element.Children); len(cs) > 0 {
+			g.rings = [][]kml.Coordinate{cs[:1]}
+		}
+		return g, nil
+	case *kml.LineStringElement:
+		return e.lineString(element.Children), nil
+	case *kml.LinearRingElement:
+		return e.lineString(element.Children), nil
+	case *kml.GxTrackElement:
+		return e.track(element.Children), nil
+	case *kml.GxMultiTrackElement:
+		g := &geometry{typ: multiLineStringType}
+		for _, child := range element.Children {
+			if track, ok := child.(*kml.GxTrackElement); ok {
+				g.children = append(g.children, e.track(track.Children))
+			}
+		}
+		return g, nil
+	case *kml.PolygonElement:
+		g := &geometry{typ: polygonType}
+		var outer [][]kml.Coordinate
+		var inners [][]kml.Coordinate
+		for _, child := range element.Children {
+			switch child := child.(type) {
+			case *kml.OuterBoundaryIsElement:
+				outer = append(outer, e.rings(child.Children)...)
+			case *kml.InnerBoundaryIsElement:
+				inners = append(inners, e.rings(child.Children)...)
+			}
+		}
+		if len(outer) > 0 {
+			g.rings = append(outer[:1], inners...)
+		}
+		return g, nil
+	case *kml.MultiGeometryElement:
+		var children []*geometry
+		childTypes := make(map[uint32]struct{})
+		for _, child := range element.Children {
+			switch child.(type) {
+			case *kml.PointElement, *kml.LineStringElement, *kml.LinearRingElement, *kml.PolygonElement, *kml.MultiGeometryElement,
+				*kml.GxTrackElement, *kml.GxMultiTrackElement:
+			default:
+				continue
+			}
+			g, err := e.geometry(child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, g)
+			childTypes[g.typ] = struct{}{}
+		}
+		typ := uint32(geometryCollectionType)
+		if len(childTypes) == 1 {
+			switch children[0].typ {
+			case pointType:
+				typ = multiPointType
+			case lineStringType:
+				typ = multiLineStringType
+			case polygonType:
+				typ = multiPolygonType
+			}
+		}
+		return &geometry{typ: typ, children: children}, nil
+	default:
+		return nil, fmt.Errorf("%T: unsupported geometry", element)
+	}
+}
+
+// lineString returns a line string geometry with the coordinates in children.
+func (e *encoder) lineString(children []kml.Element) *geometry {
+	g := &geometry{typ: lineStringType}
+	if cs := e.coordinates(children); len(cs) > 0 {
+		g.rings = [][]kml.Coordinate{cs}
+	}
+	return g
+}
+
+// rings returns the coordinates of the LinearRing elements in children.
+func (e *encoder) rings(children []kml.Element) [][]kml.Coordinate {
+	var rings [][]kml.Coordinate
+	for _, child := range children {
+		if linearRing, ok := child.(*kml.LinearRingElement); ok {
+			if cs := e.coordinates(linearRing.Children); len(cs) > 0 {
+				rings = append(rings, cs)
+			}
+		}
+	}
+	return rings
+}
+
+// track returns a line string geometry with the coordinates of the GxCoord
+// elements in children.
+func (e *encoder) track(children []kml.Element) *geometry {
+	var cs []kml.Coordinate
+	for _, child := range children {
+		if gxCoord, ok := child.(kml.GxCoordElement); ok {
+			if gxCoord.Alt != 0 {
+				e.z = true
+			}
+			cs = append(cs, kml.Coordinate(gxCoord))
+		}
+	}
+	g := &geometry{typ: lineStringType}
+	if len(cs) > 0 {
+		g.rings = [][]kml.Coordinate{cs}
+	}
+	return g
+}
+
+// coordinates returns the coordinates of the first coordinates element in
+// children and records whether they include altitudes.
+func (e *encoder) coordinates(children []kml.Element) []kml.Coordinate {
+	for _, child := range children {
+		if cs, dim := kml.CoordinatesOf(child); dim != 0 {
+			if dim > 2 {
+				e.z = true
+			}
+			return cs
+		}
+	}
+	return nil
+}